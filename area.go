@@ -0,0 +1,127 @@
+package excel
+
+import (
+	"fmt"
+	"strings"
+
+	excelize "github.com/xuri/excelize/v2"
+)
+
+// area is the rectangle of a sheet to scan, the zero value covers the whole sheet.
+// left and top are the number of columns and rows before it, right and bottom are
+// the 1-based index of its last column and row, 0 means unbounded.
+type area struct {
+	left, top     int
+	right, bottom int
+}
+
+// contains reports whether the 1-based cell coordinates are inside the area.
+func (a area) contains(col, row int) bool {
+	return col > a.left && row > a.top &&
+		(a.right == 0 || col <= a.right) && (a.bottom == 0 || row <= a.bottom)
+}
+
+// crop returns the cells of rows inside the area.
+func (a area) crop(rows [][]string) [][]string {
+	if a.bottom > 0 && len(rows) > a.bottom {
+		rows = rows[:a.bottom]
+	}
+	if len(rows) <= a.top {
+		return nil
+	}
+	rows = rows[a.top:]
+	cropped := make([][]string, len(rows))
+	for i, row := range rows {
+		if a.right > 0 && len(row) > a.right {
+			row = row[:a.right]
+		}
+		if len(row) > a.left {
+			cropped[i] = row[a.left:]
+		}
+	}
+	return cropped
+}
+
+// Range limits the scan to a cell range such as "B4:H200" or "Sheet1!B4:H200".
+func (s *Sheet) Range(ref string) *Sheet {
+	s.rangeRef = ref
+	return s
+}
+
+// DefinedName limits the scan to the range referred by a defined name of the workbook.
+func (s *Sheet) DefinedName(name string) *Sheet {
+	s.definedName = name
+	return s
+}
+
+// Table limits the scan to an Excel table, the sheet holding it is found by name.
+func (s *Sheet) Table(name string) *Sheet {
+	s.table = name
+	return s
+}
+
+// resolveArea finds the sheet and the area set by Range, DefinedName or Table.
+func (s *Sheet) resolveArea(f *excelize.File) error {
+	var ref string
+	switch {
+	case s.table != "":
+		sheet, tableRange, err := findTable(f, s.table)
+		if err != nil {
+			return err
+		}
+		s.sheet, ref = sheet, tableRange
+	case s.definedName != "":
+		refersTo, err := findDefinedName(f, s.definedName)
+		if err != nil {
+			return err
+		}
+		ref = refersTo
+	case s.rangeRef != "":
+		ref = s.rangeRef
+	default:
+		return nil
+	}
+	ref = strings.TrimPrefix(ref, "=")
+	if i := strings.LastIndex(ref, "!"); i >= 0 {
+		s.sheet = strings.ReplaceAll(strings.Trim(ref[:i], "'"), "''", "'")
+		ref = ref[i+1:]
+	}
+	first, last, ok := strings.Cut(strings.ReplaceAll(ref, "$", ""), ":")
+	if !ok {
+		return fmt.Errorf("invalid range %q", ref)
+	}
+	left, top, err := excelize.CellNameToCoordinates(first)
+	if err != nil {
+		return err
+	}
+	right, bottom, err := excelize.CellNameToCoordinates(last)
+	if err != nil {
+		return err
+	}
+	s.area = area{left: left - 1, top: top - 1, right: right, bottom: bottom}
+	return nil
+}
+
+func findTable(f *excelize.File, name string) (string, string, error) {
+	for _, sheet := range f.GetSheetList() {
+		tables, err := f.GetTables(sheet)
+		if err != nil {
+			return "", "", err
+		}
+		for _, table := range tables {
+			if table.Name == name {
+				return sheet, table.Range, nil
+			}
+		}
+	}
+	return "", "", fmt.Errorf("table %s not found", name)
+}
+
+func findDefinedName(f *excelize.File, name string) (string, error) {
+	for _, dn := range f.GetDefinedName() {
+		if dn.Name == name {
+			return dn.RefersTo, nil
+		}
+	}
+	return "", fmt.Errorf("defined name %s not found", name)
+}
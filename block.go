@@ -0,0 +1,114 @@
+package excel
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	excelize "github.com/xuri/excelize/v2"
+)
+
+// ScanBlocks reads a sheet holding several tables stacked vertically, each with its own
+// header and ended by a blank row. v must point to a [][]T, or to a map[string][]T keyed
+// by the title row above each header.
+func (s *Sheet) ScanBlocks(v any) error {
+	return s.ScanBlocksContext(context.Background(), v)
+}
+
+// ScanBlocksContext is like ScanBlocks but stops reading when ctx is done.
+func (s *Sheet) ScanBlocksContext(ctx context.Context, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || !isBlocksType(rv.Type().Elem()) {
+		return fmt.Errorf("%w: want a pointer to [][]T or map[string][]T", ErrNotSlicePointer)
+	}
+	if err := s.checkRowType(rv.Type().Elem().Elem().Elem(), opScan); err != nil {
+		return err
+	}
+	f, err := s.excelizeOpen()
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return s.scanBlocks(ctx, f, rv)
+}
+
+func isBlocksType(t reflect.Type) bool {
+	if t.Kind() != reflect.Slice && (t.Kind() != reflect.Map || t.Key().Kind() != reflect.String) {
+		return false
+	}
+	return t.Elem().Kind() == reflect.Slice && t.Elem().Elem().Kind() == reflect.Struct
+}
+
+func (s *Sheet) scanBlocks(ctx context.Context, f *excelize.File, rv reflect.Value) error {
+	bt := rv.Type().Elem()
+	st := bt.Elem()
+	t := st.Elem()
+	rows, err := s.loadRows(f)
+	if err != nil {
+		return err
+	}
+	var blocks reflect.Value
+	if bt.Kind() == reflect.Map {
+		blocks = reflect.MakeMap(bt)
+	} else {
+		blocks = reflect.MakeSlice(bt, 0, 0)
+	}
+	expected := s.expectedColumns(t)
+	header := s.headerRowCount()
+	var scanned []Row
+	from := s.offset
+	for i := s.offset; i+header <= len(rows); {
+		keys, err := s.headerKeys(f, rows, i)
+		if err != nil {
+			return err
+		}
+		if score := matchHeader(keys, expected); score == 0 || float64(score) < float64(len(expected))*detectHeaderThreshold {
+			i++
+			continue
+		}
+		end := i + header
+		for end < len(rows) && !isBlankRow(rows[end]) {
+			end++
+		}
+		items, blockRows, err := s.decodeRows(ctx, f, rows[:end], i, st)
+		if err != nil {
+			return err
+		}
+		scanned = append(scanned, blockRows...)
+		if bt.Kind() == reflect.Map {
+			title := blockTitle(rows, from, i)
+			if title == "" {
+				return fmt.Errorf("block at row %d has no title", s.area.top+i+1)
+			}
+			key := reflect.ValueOf(title).Convert(bt.Key())
+			if blocks.MapIndex(key).IsValid() {
+				return fmt.Errorf("block at row %d has the duplicate title %q", s.area.top+i+1, title)
+			}
+			blocks.SetMapIndex(key, items)
+		} else {
+			blocks = reflect.Append(blocks, items)
+		}
+		from, i = end, end
+	}
+	rv.Elem().Set(blocks)
+	if err := s.checkConstraints(f, t, scanned); err != nil {
+		return err
+	}
+	if len(s.errors) > 0 {
+		return s.errors[0]
+	}
+	return nil
+}
+
+// blockTitle returns the first cell of the nearest non-blank row between rows[from] and the header.
+func blockTitle(rows [][]string, from, header int) string {
+	for i := header - 1; i >= from; i-- {
+		for _, cell := range rows[i] {
+			if value := strings.TrimSpace(cell); value != "" {
+				return value
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,70 @@
+package excel
+
+import (
+	"fmt"
+	"strings"
+)
+
+// boolWords is a word for true and a word for false.
+type boolWords [2]string
+
+var (
+	// trueWords and falseWords are accepted by Scan regardless of case.
+	trueWords  = []string{"true", "t", "yes", "y", "1", "on", "是", "对", "真", "✓", "✔", "√"}
+	falseWords = []string{"false", "f", "no", "n", "0", "off", "否", "错", "假", "x", "×", "✗", "✘"}
+)
+
+// BoolFormat sets the words written for true and false by export, Scan accepts them along with
+// the built-in words such as yes/no, y/n, 1/0 and 是/否.
+func (s *Sheet) BoolFormat(trueWord, falseWord string) *Sheet {
+	s.bools = boolWords{trueWord, falseWord}
+	return s
+}
+
+// parseBoolWords parses the words of the bool option, e.g. `xlsx:"active,bool=是|否"`.
+func parseBoolWords(s string) boolWords {
+	t, f, ok := strings.Cut(s, "|")
+	if !ok {
+		return boolWords{}
+	}
+	return boolWords{strings.TrimSpace(t), strings.TrimSpace(f)}
+}
+
+// parseBoolWord parses v as one of words or of the built-in words.
+func parseBoolWord(v string, words ...boolWords) (bool, error) {
+	v = strings.TrimSpace(v)
+	for _, w := range words {
+		if w[0] != "" && strings.EqualFold(v, w[0]) {
+			return true, nil
+		}
+		if w[1] != "" && strings.EqualFold(v, w[1]) {
+			return false, nil
+		}
+	}
+	for _, w := range trueWords {
+		if strings.EqualFold(v, w) {
+			return true, nil
+		}
+	}
+	for _, w := range falseWords {
+		if strings.EqualFold(v, w) {
+			return false, nil
+		}
+	}
+	return false, fmt.Errorf("invalid bool %q", v)
+}
+
+// boolText returns the word written for b, the words of the field take precedence over the sheet's.
+func (s *Sheet) boolText(b bool, tag fieldTag) string {
+	words := tag.bools
+	if words == (boolWords{}) {
+		words = s.bools
+	}
+	if words == (boolWords{}) {
+		words = boolWords{"true", "false"}
+	}
+	if b {
+		return words[0]
+	}
+	return words[1]
+}
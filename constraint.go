@@ -0,0 +1,111 @@
+package excel
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	excelize "github.com/xuri/excelize/v2"
+)
+
+// checkConstraints checks the unique and ref options of the fields after the rows are decoded.
+func (s *Sheet) checkConstraints(f *excelize.File, t reflect.Type, rows []Row) error {
+	for i := 0; i < t.NumField(); i++ {
+		if ignored(t.Field(i)) {
+			continue
+		}
+		tag := parseTag(t.Field(i))
+		if tag.unique {
+			s.checkUnique(tag.name, rows)
+		}
+		if tag.ref != "" {
+			if err := s.checkRef(f, tag.name, tag.ref, rows); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *Sheet) checkUnique(name string, rows []Row) {
+	seen := make(map[string]int)
+	for _, row := range rows {
+		value := row.Get(name)
+		if value == "" {
+			continue
+		}
+		if first, ok := seen[value]; ok {
+			s.errors = append(s.errors, Error{Sheet: s.sheet, Row: row, Field: name, Value: value, mesg: fmt.Sprintf("%s: duplicate value %q in rows %d and %d", name, value, first, row.Num)})
+			continue
+		}
+		seen[value] = row.Num
+	}
+}
+
+func (s *Sheet) checkRef(f *excelize.File, name, ref string, rows []Row) error {
+	values, err := s.refValues(f, ref)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	for _, row := range rows {
+		value := row.Get(name)
+		if value == "" {
+			continue
+		}
+		if !values[value] {
+			s.errors = append(s.errors, Error{Sheet: s.sheet, Row: row, Field: name, Value: value, mesg: fmt.Sprintf("%s: value %q not found in %s", name, value, ref)})
+		}
+	}
+	return nil
+}
+
+// refSheet returns the Sheet the column of a ref option is read from. A sheet of the same Excel
+// is read with the options of the Excel, otherwise the header is searched for the column.
+func (s *Sheet) refSheet(name string) *Sheet {
+	if s.sibling != nil {
+		return s.sibling(name)
+	}
+	return &Sheet{sheet: name, detectHeader: true}
+}
+
+// refValues reads the values of the column referred by ref, which has the form "Sheet.column".
+func (s *Sheet) refValues(f *excelize.File, ref string) (map[string]bool, error) {
+	sheet, column, ok := strings.Cut(ref, ".")
+	if !ok || sheet == "" || column == "" {
+		return nil, fmt.Errorf("invalid ref %q", ref)
+	}
+	rs := s.refSheet(sheet)
+	rows, err := rs.loadRows(f)
+	if err != nil {
+		return nil, err
+	}
+	start, err := rs.findHeader(f, rows, []string{column})
+	if err != nil {
+		return nil, err
+	}
+	header := rs.headerRowCount()
+	if len(rows) < start+header {
+		return nil, fmt.Errorf("sheet %s rows less than %d", sheet, start+header)
+	}
+	keys, err := rs.headerKeys(f, rows, start)
+	if err != nil {
+		return nil, err
+	}
+	index := -1
+	for i, key := range keys {
+		if key == column {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return nil, fmt.Errorf("column %s not found in sheet %s", column, sheet)
+	}
+	values := make(map[string]bool)
+	for _, row := range rows[start+header:] {
+		if index < len(row) {
+			values[strings.TrimSpace(row[index])] = true
+		}
+	}
+	return values, nil
+}
@@ -0,0 +1,93 @@
+package excel
+
+import (
+	"reflect"
+	"sync"
+)
+
+// Converters is a set of converters for types that can not implement Marshaler and
+// Unmarshaler themselves, such as types of other packages.
+type Converters struct {
+	mu sync.RWMutex
+	m  map[reflect.Type]converter
+}
+
+type converter struct {
+	encode func(reflect.Value) (any, error)
+	decode func(string) (reflect.Value, error)
+}
+
+// converters is the global set used by every Sheet and Excel.
+var converters = NewConverters()
+
+// NewConverters creates an empty set of converters.
+func NewConverters() *Converters {
+	return &Converters{m: make(map[reflect.Type]converter)}
+}
+
+// RegisterConverter registers the converter of T in the global set. encode returns the value
+// written to the cell and decode parses the text of the cell, either of them may be nil
+// to convert T in one direction only.
+func RegisterConverter[T any](encode func(T) (any, error), decode func(string) (T, error)) {
+	AddConverter(converters, encode, decode)
+}
+
+// AddConverter is like RegisterConverter but registers the converter of T in cs, which
+// takes precedence over the global set once given to Sheet.Converters or Excel.Converters.
+func AddConverter[T any](cs *Converters, encode func(T) (any, error), decode func(string) (T, error)) *Converters {
+	var c converter
+	if encode != nil {
+		c.encode = func(v reflect.Value) (any, error) {
+			t, _ := v.Interface().(T)
+			return encode(t)
+		}
+	}
+	if decode != nil {
+		c.decode = func(s string) (reflect.Value, error) {
+			v, err := decode(s)
+			return reflect.ValueOf(&v).Elem(), err
+		}
+	}
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	cs.m[reflect.TypeFor[T]()] = c
+	return cs
+}
+
+func (cs *Converters) get(t reflect.Type) (converter, bool) {
+	if cs == nil {
+		return converter{}, false
+	}
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	c, ok := cs.m[t]
+	return c, ok
+}
+
+// Converters sets the converters consulted before the global ones.
+func (s *Sheet) Converters(cs *Converters) *Sheet {
+	s.converters = cs
+	return s
+}
+
+// encoder returns the encode function registered for t.
+func (s *Sheet) encoder(t reflect.Type) func(reflect.Value) (any, error) {
+	if c, ok := s.converters.get(t); ok && c.encode != nil {
+		return c.encode
+	}
+	if c, ok := converters.get(t); ok && c.encode != nil {
+		return c.encode
+	}
+	return nil
+}
+
+// decoder returns the decode function registered for t.
+func (s *Sheet) decoder(t reflect.Type) func(string) (reflect.Value, error) {
+	if c, ok := s.converters.get(t); ok && c.decode != nil {
+		return c.decode
+	}
+	if c, ok := converters.get(t); ok && c.decode != nil {
+		return c.decode
+	}
+	return nil
+}
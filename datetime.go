@@ -0,0 +1,93 @@
+package excel
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	excelize "github.com/xuri/excelize/v2"
+)
+
+// locations caches the locations of the tz option by name.
+var locations sync.Map
+
+// location returns the location of the tz option, or nil if there is none.
+func (tag fieldTag) location() (*time.Location, error) {
+	if tag.tz == "" {
+		return nil, nil
+	}
+	if loc, ok := locations.Load(tag.tz); ok {
+		return loc.(*time.Location), nil
+	}
+	loc, err := time.LoadLocation(tag.tz)
+	if err != nil {
+		return nil, err
+	}
+	locations.Store(tag.tz, loc)
+	return loc, nil
+}
+
+// timeLayouts returns the layouts of a time field, the layout option or its alias format
+// holds them separated by "|", e.g. `xlsx:"date,layout=2006/01/02|02.01.2006 15:04"`.
+// The layout option of a row field never means the layout of a sheet, which is only
+// given to the fields of a workbook.
+func (tag fieldTag) timeLayouts() []string {
+	layouts := tag.format
+	if layouts == "" {
+		layouts = tag.layout
+	}
+	if layouts == "" {
+		return nil
+	}
+	return strings.Split(layouts, "|")
+}
+
+// parseTime parses a time cell from its text with the layouts of the layout option, then from
+// its raw value as a date serial, and last from the RFC 3339 text written by time.Time.MarshalText.
+// The wall clock of the cell is read in the location of the tz option, UTC by default.
+func (s *Sheet) parseTime(raw, text string, tag fieldTag) (time.Time, error) {
+	loc, err := tag.location()
+	if err != nil {
+		return time.Time{}, err
+	}
+	in := loc
+	if in == nil {
+		in = time.UTC
+	}
+	layouts := tag.timeLayouts()
+	for _, layout := range layouts {
+		if t, err := time.ParseInLocation(layout, text, in); err == nil {
+			return t, nil
+		}
+	}
+	if v, err := strconv.ParseFloat(raw, 64); err == nil {
+		t, err := excelize.ExcelDateToTime(v, s.date1904)
+		if err != nil || loc == nil {
+			return t, err
+		}
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), loc), nil
+	}
+	t, err := time.Parse(time.RFC3339, text)
+	if err != nil && len(layouts) > 0 {
+		return t, fmt.Errorf("%q does not match layout %s", text, strings.Join(layouts, " or "))
+	}
+	return t, err
+}
+
+// timeValue returns the value written for t, which is t in the location of the tz option,
+// formatted with the first layout of the layout option if there is one.
+func timeValue(t time.Time, tag fieldTag) (any, error) {
+	loc, err := tag.location()
+	if err != nil {
+		return nil, err
+	}
+	if loc != nil {
+		t = t.In(loc)
+	}
+	if layouts := tag.timeLayouts(); len(layouts) > 0 {
+		return t.Format(layouts[0]), nil
+	}
+	return t, nil
+}
@@ -0,0 +1,50 @@
+package excel
+
+import "strings"
+
+// EmptyPolicy decides what Scan does with a blank cell, or with a column missing from the sheet.
+type EmptyPolicy int
+
+const (
+	// EmptyDefault uses the default option of the field if it has one, and leaves the
+	// zero value, or nil for a pointer field, otherwise.
+	EmptyDefault EmptyPolicy = iota
+	// EmptyZero always leaves the zero value, or nil for a pointer field, ignoring the default option.
+	EmptyZero
+	// EmptyRequired is like EmptyDefault, but a field with the required option or the required
+	// validation and no default is reported with ErrMissingColumn when its column is missing
+	// from the sheet, and with ErrBlankCell when its cell is blank.
+	EmptyRequired
+)
+
+// EmptyCells sets the policy for blank cells and missing columns, EmptyDefault by default.
+func (s *Sheet) EmptyCells(policy EmptyPolicy) *Sheet {
+	s.empty = policy
+	return s
+}
+
+// emptyValue returns the text decoded for a blank cell, or for a missing column of a field,
+// an empty text leaves the field as it is.
+func (s *Sheet) emptyValue(tag fieldTag, valid string, missing bool) (string, error) {
+	if s.empty != EmptyZero && tag.def != "" {
+		return tag.def, nil
+	}
+	if s.empty == EmptyRequired && (tag.required || hasRule(valid, "required")) {
+		if missing {
+			return "", ErrMissingColumn
+		}
+		return "", ErrBlankCell
+	}
+	return "", nil
+}
+
+// hasRule reports whether the validate tag has the rule, ignoring its parameter.
+func hasRule(valid, rule string) bool {
+	for _, item := range strings.Split(valid, ",") {
+		name, _, _ := strings.Cut(item, "=")
+		if strings.TrimSpace(name) == rule {
+			return true
+		}
+	}
+	return false
+}
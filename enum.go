@@ -0,0 +1,77 @@
+package excel
+
+import (
+	"reflect"
+	"strings"
+
+	excelize "github.com/xuri/excelize/v2"
+)
+
+// enumItem is a stored value and its label in the enum option,
+// e.g. `xlsx:"status,enum=1:Active;2:Suspended"`.
+type enumItem struct {
+	value string
+	label string
+}
+
+func parseEnum(s string) []enumItem {
+	var items []enumItem
+	for _, pair := range strings.Split(s, ";") {
+		value, label, ok := strings.Cut(pair, ":")
+		if !ok {
+			continue
+		}
+		items = append(items, enumItem{value: strings.TrimSpace(value), label: strings.TrimSpace(label)})
+	}
+	return items
+}
+
+// enumLabel returns the label of value, or value itself if it has none.
+func (tag fieldTag) enumLabel(value string) string {
+	for _, item := range tag.enum {
+		if item.value == value {
+			return item.label
+		}
+	}
+	return value
+}
+
+// enumValue returns the stored value of label.
+func (tag fieldTag) enumValue(label string) (string, bool) {
+	for _, item := range tag.enum {
+		if strings.EqualFold(item.label, label) {
+			return item.value, true
+		}
+	}
+	return "", false
+}
+
+// addEnumValidations adds a dropdown of the labels below the header of every enum column.
+func (s *Sheet) addEnumValidations(f *excelize.File, t reflect.Type) error {
+	col := 0
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !exported(field) || !s.shown(field) {
+			continue
+		}
+		col++
+		tag := parseTag(field)
+		if len(tag.enum) == 0 {
+			continue
+		}
+		labels := make([]string, 0, len(tag.enum))
+		for _, item := range tag.enum {
+			labels = append(labels, item.label)
+		}
+		dv := excelize.NewDataValidation(true)
+		dv.Sqref = cell(s.headerRowCount()+1, col) + ":" + cell(excelize.TotalRows, col)
+		// a list longer than excel allows is left without a dropdown
+		if err := dv.SetDropList(labels); err != nil {
+			continue
+		}
+		if err := f.AddDataValidation(s.sheet, dv); err != nil {
+			return err
+		}
+	}
+	return nil
+}
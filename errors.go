@@ -0,0 +1,146 @@
+package excel
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+var (
+	// ErrNotSlicePointer is returned when a sheet is given anything but a non-nil pointer to a slice of structs.
+	ErrNotSlicePointer = errors.New("param must be a pointer to a slice of structs")
+	// ErrNotStructPointer is returned when a workbook is given anything but a non-nil pointer to a struct.
+	ErrNotStructPointer = errors.New("param must be a pointer to a struct")
+	// ErrUnsupportedFieldType is returned when a field of the row type can not be read or written.
+	ErrUnsupportedFieldType = errors.New("unsupported field type")
+	// ErrUnknownEnumLabel is reported by Scan for a cell that holds none of the labels of the enum option.
+	ErrUnknownEnumLabel = errors.New("unknown enum label")
+	// ErrMissingColumn is reported by Scan under EmptyRequired for a required field whose column is missing.
+	ErrMissingColumn = errors.New("column is missing")
+	// ErrBlankCell is reported by Scan under EmptyRequired for a required field whose cell is blank.
+	ErrBlankCell = errors.New("cell is blank")
+	// ErrPrecisionLoss is reported by Scan for an integer in a numeric cell beyond the 15 digits excel keeps.
+	ErrPrecisionLoss = errors.New("number exceeds the 15 digits excel keeps")
+)
+
+type operation int
+
+const (
+	opScan operation = iota
+	opExport
+	opStreamExport
+)
+
+// checkSlice checks that v points to a slice of structs whose fields op can handle.
+func (s *Sheet) checkSlice(v any, op operation) (reflect.Value, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Slice ||
+		rv.Type().Elem().Elem().Kind() != reflect.Struct {
+		return reflect.Value{}, ErrNotSlicePointer
+	}
+	if err := s.checkRowType(rv.Type().Elem().Elem(), op); err != nil {
+		return reflect.Value{}, err
+	}
+	return rv, nil
+}
+
+// checkBook checks that v points to a struct whose fields are sheets op can handle.
+func (e Excel) checkBook(v any, op operation) (reflect.Value, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return reflect.Value{}, ErrNotStructPointer
+	}
+	rt := rv.Type().Elem()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if ignored(field) {
+			continue
+		}
+		t, sheetOp := field.Type, op
+		if parseTag(field).layout == layoutKV {
+			if t.Kind() != reflect.Struct {
+				return reflect.Value{}, unsupportedField(field)
+			}
+			// key-value sheets are never written with a stream writer
+			if op == opStreamExport {
+				sheetOp = opExport
+			}
+		} else {
+			if t.Kind() != reflect.Slice || t.Elem().Kind() != reflect.Struct {
+				return reflect.Value{}, unsupportedField(field)
+			}
+			t = t.Elem()
+		}
+		if err := e.newSheet(field.Name).checkRowType(t, sheetOp); err != nil {
+			return reflect.Value{}, err
+		}
+	}
+	return rv, nil
+}
+
+// checkRowType returns ErrUnsupportedFieldType for the first field of t that op can not handle.
+func (s *Sheet) checkRowType(t reflect.Type, op operation) error {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if ignored(field) {
+			continue
+		}
+		var ok bool
+		switch {
+		case parseTag(field).meta():
+			ok = op != opScan || basicKind(field.Type)
+		case op == opScan:
+			ok = s.scannable(field.Type)
+		default:
+			ok = s.exportable(field.Type, op == opStreamExport)
+		}
+		if !ok {
+			return unsupportedField(field)
+		}
+	}
+	return nil
+}
+
+func unsupportedField(field reflect.StructField) error {
+	return fmt.Errorf("%w: field %s of type %s", ErrUnsupportedFieldType, field.Name, field.Type)
+}
+
+func basicKind(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+func (s *Sheet) scannable(t reflect.Type) bool {
+	if s.decoder(t) != nil {
+		return true
+	}
+	if t.Kind() == reflect.Pointer {
+		return s.scannable(t.Elem())
+	}
+	if implements(t, scanInterfaceTypes) {
+		return true
+	}
+	if t.Kind() == reflect.Struct {
+		return isTime(t) || t == picReflectType || t == cellReflectType
+	}
+	return basicKind(t)
+}
+
+func (s *Sheet) exportable(t reflect.Type, stream bool) bool {
+	if s.encoder(t) != nil || implements(t, exportInterfaceTypes) {
+		return true
+	}
+	if t.Kind() == reflect.Pointer {
+		return s.exportable(t.Elem(), stream)
+	}
+	if t.Kind() == reflect.Struct {
+		return isTime(t) || isSerial(t) || !stream && (t == picReflectType || t == cellReflectType)
+	}
+	return basicKind(t)
+}
@@ -2,30 +2,56 @@ package excel
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"io"
 	"reflect"
-	"strings"
+	"regexp"
+	"time"
 
+	"github.com/go-playground/validator/v10"
 	excelize "github.com/xuri/excelize/v2"
 )
 
 type Excel struct {
-	filename     string
-	reader       io.Reader
-	offset       int
-	style        int
-	useTextStyle bool
+	filename      string
+	reader        io.Reader
+	offset        int
+	headerRows    int
+	detectHeader  bool
+	detectRows    int
+	stopAtBlank   bool
+	stopAt        func(string) bool
+	limit         int
+	skip          int
+	style         int
+	useTextStyle  bool
+	fillMerged    bool
+	collectErrors bool
+	report        *scanReport
+	validate      *validator.Validate
+	locale        string
+	translation   *translation
+	converters    *Converters
+	bools         boolWords
+	empty         EmptyPolicy
+	rawValue      bool
+	decimal       rune
+	group         rune
+
+	progress         ProgressFunc
+	progressInterval int
+	started          time.Time
 }
 
 // Create a new Excel instance with filename.
 func NewExcel(filename string) *Excel {
-	return &Excel{filename: filename}
+	return &Excel{filename: filename, report: &scanReport{}}
 }
 
 // Create a new Excel instance with io.Reader.
 func NewExcelFromReader(r io.Reader) *Excel {
-	return &Excel{reader: r}
+	return &Excel{reader: r, report: &scanReport{}}
 }
 
 // UseTextStyle sets the style of the cell to text.
@@ -40,12 +66,179 @@ func (e *Excel) Offset(n int) *Excel {
 	return e
 }
 
-func getFieldName(field reflect.StructField) string {
-	tag := strings.TrimSpace(field.Tag.Get("xlsx"))
-	if tag != "" {
-		return tag
+// HeaderRows sets the number of header rows of every sheet.
+func (e *Excel) HeaderRows(n int) *Excel {
+	e.headerRows = n
+	return e
+}
+
+// DetectHeader searches the header of every sheet instead of taking the row at the offset,
+// HeaderRow returns the row found in each sheet.
+func (e *Excel) DetectHeader() *Excel {
+	e.detectHeader = true
+	e.scanReport()
+	return e
+}
+
+// DetectHeaderRows is like DetectHeader but searches the first k rows after the offset.
+func (e *Excel) DetectHeaderRows(k int) *Excel {
+	e.detectRows = k
+	return e.DetectHeader()
+}
+
+// HeaderRow returns the 0-based index of the header row of sheet found by the last scan,
+// it reports false for a sheet that was not scanned.
+func (e *Excel) HeaderRow(sheet string) (int, bool) {
+	row, ok := e.scanReport().headerRows[sheet]
+	return row, ok
+}
+
+// StopAtBlankRow stops scanning every sheet at its first blank row.
+func (e *Excel) StopAtBlankRow() *Excel {
+	e.stopAtBlank = true
+	return e
+}
+
+// StopAt stops scanning every sheet at the first row whose first cell satisfies fn.
+func (e *Excel) StopAt(fn func(first string) bool) *Excel {
+	e.stopAt = fn
+	return e
+}
+
+// StopAtRegexp stops scanning every sheet at the first row whose first cell matches re.
+func (e *Excel) StopAtRegexp(re *regexp.Regexp) *Excel {
+	return e.StopAt(re.MatchString)
+}
+
+// Limit sets the maximum number of rows to scan in every sheet, 0 means no limit.
+func (e *Excel) Limit(n int) *Excel {
+	e.limit = n
+	return e
+}
+
+// Skip skips the first n non-blank rows after the header of every sheet.
+func (e *Excel) Skip(n int) *Excel {
+	e.skip = n
+	return e
+}
+
+// FillMergedCells copies the value of a merged cell into every row and column it covers while scanning.
+func (e *Excel) FillMergedCells() *Excel {
+	e.fillMerged = true
+	return e
+}
+
+// Validator sets the validator used for the validate tag of every sheet.
+func (e *Excel) Validator(v *validator.Validate) *Excel {
+	e.validate = v
+	e.translate()
+	return e
+}
+
+// Locale sets the language of validation messages, "en" and "zh" are supported.
+func (e *Excel) Locale(locale string) *Excel {
+	e.locale = locale
+	e.translate()
+	return e
+}
+
+// BoolFormat sets the words written for true and false in every sheet.
+func (e *Excel) BoolFormat(trueWord, falseWord string) *Excel {
+	e.bools = boolWords{trueWord, falseWord}
+	return e
+}
+
+// EmptyCells sets the policy for blank cells and missing columns of every sheet.
+func (e *Excel) EmptyCells(policy EmptyPolicy) *Excel {
+	e.empty = policy
+	return e
+}
+
+// RawCellValue reads the values of cells in every sheet without their number format applied.
+func (e *Excel) RawCellValue() *Excel {
+	e.rawValue = true
+	return e
+}
+
+// NumberFormat sets the decimal and grouping separators of numbers stored as text in every sheet.
+func (e *Excel) NumberFormat(decimal, group rune) *Excel {
+	e.decimal, e.group = decimal, group
+	return e
+}
+
+// Converters sets the converters consulted before the global ones for every sheet.
+func (e *Excel) Converters(cs *Converters) *Excel {
+	e.converters = cs
+	return e
+}
+
+// OnProgress registers fn to be called every interval rows of every sheet while scanning or exporting.
+// A non-positive interval means every 1000 rows.
+func (e *Excel) OnProgress(interval int, fn ProgressFunc) *Excel {
+	e.progressInterval = interval
+	e.progress = fn
+	return e
+}
+
+// CollectErrors collects the errors of every sheet while scanning instead of stopping at the first one,
+// Scan still returns the first of them.
+func (e *Excel) CollectErrors() *Excel {
+	e.collectErrors = true
+	e.scanReport()
+	return e
+}
+
+// Errors returns the errors of every sheet collected by the last scan.
+func (e *Excel) Errors() []Error {
+	return e.scanReport().errors
+}
+
+// scanReport holds the results of the last scan, it is shared with the copies of the Excel
+// made by its value methods.
+type scanReport struct {
+	errors     []Error
+	headerRows map[string]int
+}
+
+func (e *Excel) scanReport() *scanReport {
+	if e.report == nil {
+		e.report = &scanReport{}
 	}
-	return field.Name
+	return e.report
+}
+
+// newSheet creates a Sheet that inherits the options of the Excel.
+func (e Excel) newSheet(name string) *Sheet {
+	s := &Sheet{
+		sheet:         name,
+		offset:        e.offset,
+		headerRows:    e.headerRows,
+		detectHeader:  e.detectHeader,
+		detectRows:    e.detectRows,
+		stopAtBlank:   e.stopAtBlank,
+		stopAt:        e.stopAt,
+		limit:         e.limit,
+		skip:          e.skip,
+		style:         e.style,
+		useTextStyle:  e.useTextStyle,
+		fillMerged:    e.fillMerged,
+		collectErrors: e.collectErrors,
+		validate:      e.validate,
+		locale:        e.locale,
+		translation:   e.translation,
+		converters:    e.converters,
+		bools:         e.bools,
+		empty:         e.empty,
+		rawValue:      e.rawValue,
+		decimal:       e.decimal,
+		group:         e.group,
+
+		progress:         e.progress,
+		progressInterval: e.progressInterval,
+		started:          e.started,
+	}
+	s.sibling = e.newSheet
+	return s
 }
 
 func (e Excel) excelizeOpen() (*excelize.File, error) {
@@ -59,46 +252,77 @@ func (e Excel) excelizeOpen() (*excelize.File, error) {
 
 // Scan reads the data from the Excel file and stores it in the struct pointed to by Slice.
 func (e Excel) Scan(v any) error {
-	rv := reflect.ValueOf(v)
+	return e.ScanContext(context.Background(), v)
+}
 
-	if rv.Kind() != reflect.Ptr {
-		return errors.New("param type must be ptr")
+// ScanContext is like Scan but stops reading when ctx is done.
+func (e Excel) ScanContext(ctx context.Context, v any) error {
+	e.started = time.Now()
+	rv, err := e.checkBook(v, opScan)
+	if err != nil {
+		return err
 	}
 	rv = rv.Elem()
-	if rv.Kind() != reflect.Struct {
-		return errors.New("param type must be struct ptr")
-	}
 	rt := rv.Type()
 	f, err := e.excelizeOpen()
 	if err != nil {
 		return err
 	}
 	defer f.Close()
+	if e.report != nil {
+		*e.report = scanReport{}
+	}
+	var first error
 	for i := 0; i < rt.NumField(); i++ {
-		NewSheet(getFieldName(rt.Field(i))).Offset(e.offset).scanSheet(f, rv.Field(i).Addr())
+		if ignored(rt.Field(i)) {
+			continue
+		}
+		tag := parseTag(rt.Field(i))
+		sheet := e.newSheet(tag.name)
+		if tag.layout == layoutKV {
+			err = sheet.scanKV(ctx, f, rv.Field(i).Addr())
+		} else {
+			err = sheet.scanSheet(ctx, f, rv.Field(i).Addr())
+		}
+		if err != nil {
+			// the errors of the cells are collected, the others stop scanning
+			var cellErr Error
+			if !e.collectErrors || e.report == nil || !errors.As(err, &cellErr) {
+				return err
+			}
+			e.report.errors = append(e.report.errors, sheet.errors...)
+			if first == nil {
+				first = err
+			}
+		}
+		if e.report != nil && tag.layout != layoutKV {
+			if e.report.headerRows == nil {
+				e.report.headerRows = make(map[string]int)
+			}
+			e.report.headerRows[tag.name] = sheet.headerRow
+		}
 	}
-
-	return nil
+	return first
 }
 
-func (e *Excel) export(f *excelize.File, v any) error {
-	rv := reflect.ValueOf(v)
-
-	if rv.Kind() != reflect.Ptr {
-		return errors.New("param type must be ptr")
-	}
+func (e *Excel) export(ctx context.Context, f *excelize.File, rv reflect.Value) error {
+	e.started = time.Now()
 	rv = rv.Elem()
-	if rv.Kind() != reflect.Struct {
-		return errors.New("param type must be struct ptr")
-	}
 	rt := rv.Type()
 	deleteDefaultSheet := true
 	for i := 0; i < rt.NumField(); i++ {
-		sheet := &Sheet{sheet: getFieldName(rt.Field(i))}
-		if e.useTextStyle {
-			sheet.UseTextStyle()
+		if ignored(rt.Field(i)) {
+			continue
 		}
-		if err := sheet.sheetExport(f, rv.Field(i).Addr()); err != nil {
+		tag := parseTag(rt.Field(i))
+		sheet := e.newSheet(tag.name)
+		var err error
+		if tag.layout == layoutKV {
+			err = sheet.exportKV(f, rv.Field(i).Addr())
+		} else {
+			err = sheet.sheetExport(ctx, f, rv.Field(i).Addr())
+		}
+		if err != nil {
 			return err
 		}
 		if sheet.sheet == defaultSheet {
@@ -113,9 +337,18 @@ func (e *Excel) export(f *excelize.File, v any) error {
 
 // Export exports the struct pointed to by Slice to an byte buffer.
 func (e Excel) Export(v any) (*bytes.Buffer, error) {
+	return e.ExportContext(context.Background(), v)
+}
+
+// ExportContext is like Export but stops writing when ctx is done.
+func (e Excel) ExportContext(ctx context.Context, v any) (*bytes.Buffer, error) {
+	rv, err := e.checkBook(v, opExport)
+	if err != nil {
+		return nil, err
+	}
 	f := excelize.NewFile()
 	defer f.Close()
-	if err := e.export(f, v); err != nil {
+	if err := e.export(ctx, f, rv); err != nil {
 		return nil, err
 	}
 	return f.WriteToBuffer()
@@ -123,25 +356,22 @@ func (e Excel) Export(v any) (*bytes.Buffer, error) {
 
 // Export exports the struct pointed to by Slice to an io.Writer.
 func (e Excel) ExportTo(w io.Writer, v any) error {
+	rv, err := e.checkBook(v, opExport)
+	if err != nil {
+		return err
+	}
 	f := excelize.NewFile()
 	defer f.Close()
-	if err := e.export(f, v); err != nil {
+	if err := e.export(context.Background(), f, rv); err != nil {
 		return err
 	}
-	_, err := f.WriteTo(w)
+	_, err = f.WriteTo(w)
 	return err
 }
 
-func (e *Excel) streamExport(f *excelize.File, v any) error {
-	rv := reflect.ValueOf(v)
-
-	if rv.Kind() != reflect.Ptr {
-		return errors.New("param type must be ptr")
-	}
+func (e *Excel) streamExport(ctx context.Context, f *excelize.File, rv reflect.Value) error {
+	e.started = time.Now()
 	rv = rv.Elem()
-	if rv.Kind() != reflect.Struct {
-		return errors.New("param type must be struct ptr")
-	}
 	rt := rv.Type()
 	style, err := f.NewStyle(&excelize.Style{
 		NumFmt: 49,
@@ -152,11 +382,17 @@ func (e *Excel) streamExport(f *excelize.File, v any) error {
 	e.style = style
 	deleteDefaultSheet := true
 	for i := 0; i < rt.NumField(); i++ {
-		sheet := &Sheet{sheet: getFieldName(rt.Field(i)), style: style}
-		if e.useTextStyle {
-			sheet.UseTextStyle()
+		if ignored(rt.Field(i)) {
+			continue
 		}
-		if err := sheet.sheetStreamExport(f, rv.Field(i).Addr()); err != nil {
+		tag := parseTag(rt.Field(i))
+		sheet := e.newSheet(tag.name)
+		if tag.layout == layoutKV {
+			err = sheet.exportKV(f, rv.Field(i).Addr())
+		} else {
+			err = sheet.sheetStreamExport(ctx, f, rv.Field(i).Addr())
+		}
+		if err != nil {
 			return err
 		}
 		if sheet.sheet == defaultSheet {
@@ -171,9 +407,18 @@ func (e *Excel) streamExport(f *excelize.File, v any) error {
 
 // StreamExport exports the struct pointed to by Slice to an byte buffer.
 func (e Excel) StreamExport(v any) (*bytes.Buffer, error) {
+	return e.StreamExportContext(context.Background(), v)
+}
+
+// StreamExportContext is like StreamExport but stops writing when ctx is done.
+func (e Excel) StreamExportContext(ctx context.Context, v any) (*bytes.Buffer, error) {
+	rv, err := e.checkBook(v, opStreamExport)
+	if err != nil {
+		return nil, err
+	}
 	f := excelize.NewFile()
 	defer f.Close()
-	if err := e.streamExport(f, v); err != nil {
+	if err := e.streamExport(ctx, f, rv); err != nil {
 		return nil, err
 	}
 	return f.WriteToBuffer()
@@ -181,11 +426,15 @@ func (e Excel) StreamExport(v any) (*bytes.Buffer, error) {
 
 // StreamExport exports the struct pointed to by Slice to an io.Writer.
 func (e Excel) StreamExportTo(w io.Writer, v any) error {
+	rv, err := e.checkBook(v, opStreamExport)
+	if err != nil {
+		return err
+	}
 	f := excelize.NewFile()
 	defer f.Close()
-	if err := e.streamExport(f, v); err != nil {
+	if err := e.streamExport(context.Background(), f, rv); err != nil {
 		return err
 	}
-	_, err := f.WriteTo(w)
+	_, err = f.WriteTo(w)
 	return err
 }
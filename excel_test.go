@@ -1,9 +1,15 @@
 package excel
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"testing"
+	"time"
+
+	excelize "github.com/xuri/excelize/v2"
 )
 
 type Human struct {
@@ -102,6 +108,142 @@ func TestScanFromReader(t *testing.T) {
 	}
 }
 
+type Customer struct {
+	ID   string `xlsx:"id,unique"`
+	Name string `xlsx:"name"`
+}
+
+type Order struct {
+	ID         string `xlsx:"id,unique"`
+	CustomerID string `xlsx:"customer_id,ref=Customers.id"`
+}
+
+type OrderExample struct {
+	Customers []Customer `xlsx:"Customers"`
+	Orders    []Order    `xlsx:"Orders"`
+}
+
+func TestScanUniqueAndRef(t *testing.T) {
+	buff, err := Excel{}.Export(&OrderExample{
+		Customers: []Customer{{"C1", "Smith"}, {"C2", "Jack"}},
+		Orders:    []Order{{"O1", "C1"}, {"O2", "C2"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var data OrderExample
+	if err := NewExcelFromReader(bytes.NewReader(buff.Bytes())).Scan(&data); err != nil {
+		t.Fatal(err)
+	}
+
+	buff, err = Excel{}.Export(&OrderExample{
+		Customers: []Customer{{"C1", "Smith"}, {"C2", "Jack"}},
+		Orders:    []Order{{"O1", "C1"}, {"O1", "C3"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sheet := NewSheetFromReader(bytes.NewReader(buff.Bytes()), "Orders").CollectErrors()
+	var orders []Order
+	if err := sheet.Scan(&orders); err == nil {
+		t.Fatal("expected constraint errors")
+	}
+	errs := sheet.Errors()
+	if len(errs) != 2 {
+		t.Fatalf("expected 2 errors, got %v", errs)
+	}
+	if errs[0].Error() != `id: duplicate value "O1" in rows 2 and 3` {
+		t.Errorf("unexpected message %q", errs[0].Error())
+	}
+	if errs[1].Error() != `customer_id: value "C3" not found in Customers.id` || errs[1].Row.ID != 2 {
+		t.Errorf("unexpected error %q", errs[1].Error())
+	}
+}
+
+func TestConstraintRowNumbers(t *testing.T) {
+	f := excelize.NewFile()
+	defer f.Close()
+	f.SetSheetName("Sheet1", "Customers")
+	f.NewSheet("Orders")
+	f.SetSheetRow("Customers", "A1", &[]any{"id", "name"})
+	f.SetSheetRow("Customers", "A2", &[]any{"C1", "Smith"})
+	f.SetSheetRow("Customers", "A3", &[]any{"C1", "Jack"})
+	f.SetSheetRow("Orders", "A1", &[]any{"Orders of 2026"})
+	f.SetSheetRow("Orders", "A2", &[]any{"id", "customer_id"})
+	f.SetSheetRow("Orders", "A3", &[]any{"O1", "C1"})
+	f.SetSheetRow("Orders", "A4", &[]any{"O1", "C3"})
+	buff, _ := f.WriteToBuffer()
+
+	sheet := NewSheetFromReader(bytes.NewReader(buff.Bytes()), "Orders").Offset(1).CollectErrors()
+	var orders []Order
+	if err := sheet.Scan(&orders); err == nil {
+		t.Fatal("expected constraint errors")
+	}
+	errs := sheet.Errors()
+	if len(errs) != 2 || errs[0].Error() != `id: duplicate value "O1" in rows 3 and 4` ||
+		errs[1].Error() != `customer_id: value "C3" not found in Customers.id` || errs[1].Row.Num != 4 {
+		t.Errorf("unexpected errors %v", errs)
+	}
+
+	f.InsertRows("Customers", 1, 1)
+	f.SetCellStr("Customers", "A1", "Customers")
+	buff, _ = f.WriteToBuffer()
+	book := NewExcelFromReader(buff).Offset(1).CollectErrors()
+	var data OrderExample
+	if err := book.Scan(&data); err == nil {
+		t.Fatal("expected constraint errors")
+	}
+	errs = book.Errors()
+	if len(errs) != 3 || errs[0].Sheet != "Customers" || errs[0].Error() != `id: duplicate value "C1" in rows 3 and 4` ||
+		errs[1].Sheet != "Orders" || errs[2].Error() != `customer_id: value "C3" not found in Customers.id` {
+		t.Errorf("unexpected errors %v", errs)
+	}
+	if len(data.Orders) != 2 {
+		t.Errorf("unexpected data %v", data)
+	}
+}
+
+func TestDetectHeaderRows(t *testing.T) {
+	f := excelize.NewFile()
+	defer f.Close()
+	f.SetSheetName("Sheet1", "Human")
+	f.NewSheet("Animal")
+	for i := 1; i <= 25; i++ {
+		f.SetCellStr("Human", fmt.Sprintf("A%d", i), fmt.Sprintf("note %d", i))
+	}
+	f.SetSheetRow("Human", "A26", &[]any{"id", "name"})
+	f.SetSheetRow("Human", "A27", &[]any{1, "Smith"})
+	f.SetSheetRow("Animal", "A3", &[]any{"id", "name"})
+	f.SetSheetRow("Animal", "A4", &[]any{1, "Wolverine"})
+	buff, _ := f.WriteToBuffer()
+
+	var data ExcelExample
+	if err := NewExcelFromReader(bytes.NewReader(buff.Bytes())).DetectHeader().Scan(&data); err == nil {
+		t.Error("expected header not found error beyond the first 20 rows")
+	}
+	book := NewExcelFromReader(bytes.NewReader(buff.Bytes())).DetectHeaderRows(30)
+	if err := book.Scan(&data); err != nil {
+		t.Fatal(err)
+	}
+	if len(data.Human) != 1 || len(data.Animal) != 1 {
+		t.Errorf("unexpected data %v", data)
+	}
+	if row, ok := book.HeaderRow("Human"); !ok || row != 25 {
+		t.Errorf("unexpected header row of Human %d", row)
+	}
+	if row, ok := book.HeaderRow("Animal"); !ok || row != 2 {
+		t.Errorf("unexpected header row of Animal %d", row)
+	}
+	if _, ok := book.HeaderRow("Plant"); ok {
+		t.Error("unexpected header row of a sheet not scanned")
+	}
+	sheet := NewSheetFromReader(bytes.NewReader(buff.Bytes()), "Human").DetectHeaderRows(26)
+	var humans []Human
+	if err := sheet.Scan(&humans); err != nil || sheet.HeaderRow() != 25 {
+		t.Errorf("unexpected header row %d, %v", sheet.HeaderRow(), err)
+	}
+}
+
 func BenchmarkExport(b *testing.B) {
 	example := ExcelExample{
 		Human:  []Human{{1, "Smith"}},
@@ -131,3 +273,83 @@ func BenchmarkStreamExport(b *testing.B) {
 		Excel{}.StreamExport(&example)
 	}
 }
+
+func TestContextCanceled(t *testing.T) {
+	example := ExcelExample{
+		Human:  []Human{{1, "Smith"}},
+		Animal: []Animal{{1, "Wolverine"}},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := (Excel{}).ExportContext(ctx, &example); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if _, err := (Excel{}).StreamExportContext(ctx, &example); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	buff, err := Excel{}.Export(&example)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var data ExcelExample
+	if err := NewExcelFromReader(buff).ScanContext(ctx, &data); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+type Settings struct {
+	Title   string  `xlsx:"title"`
+	Rate    float64 `xlsx:"rate"`
+	Enabled bool    `xlsx:"enabled"`
+}
+
+type SettingsExample struct {
+	Settings Settings `xlsx:"Settings,layout=kv"`
+	Human    []Human  `xlsx:"Human"`
+}
+
+func TestKeyValueLayout(t *testing.T) {
+	example := SettingsExample{
+		Settings: Settings{"Report", 0.25, true},
+		Human:    []Human{{1, "Smith"}},
+	}
+	for _, stream := range []bool{false, true} {
+		var buff *bytes.Buffer
+		var err error
+		if stream {
+			buff, err = Excel{}.StreamExport(&example)
+		} else {
+			buff, err = Excel{}.Export(&example)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		var data SettingsExample
+		if err := NewExcelFromReader(buff).Scan(&data); err != nil {
+			t.Fatal(err)
+		}
+		if fmt.Sprint(data) != fmt.Sprint(example) {
+			t.Errorf("unexpected data %v", data)
+		}
+	}
+}
+
+type DefaultSettingsExample struct {
+	Settings TestDefaultObject `xlsx:"Settings,layout=kv"`
+}
+
+func TestKeyValueDefault(t *testing.T) {
+	f := excelize.NewFile()
+	defer f.Close()
+	f.SetSheetName("Sheet1", "Settings")
+	f.SetSheetRow("Settings", "A1", &[]any{"id", 5})
+	buff, _ := f.WriteToBuffer()
+	var data DefaultSettingsExample
+	if err := NewExcelFromReader(buff).Scan(&data); err != nil {
+		t.Fatal(err)
+	}
+	if data.Settings.ID != 5 || !data.Settings.When.Equal(time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)) ||
+		data.Settings.Start != (TimeOfDay{8, 30, 0}) {
+		t.Errorf("unexpected data %v", data)
+	}
+}
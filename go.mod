@@ -5,13 +5,13 @@ go 1.26.2
 require (
 	github.com/cuishu/functools v0.0.0-20260316063106-97db0c5fa42f
 	github.com/gabriel-vasile/mimetype v1.4.13
+	github.com/go-playground/locales v0.14.1
+	github.com/go-playground/universal-translator v0.18.1
 	github.com/go-playground/validator/v10 v10.30.2
 	github.com/xuri/excelize/v2 v2.10.1
 )
 
 require (
-	github.com/go-playground/locales v0.14.1 // indirect
-	github.com/go-playground/universal-translator v0.18.1 // indirect
 	github.com/leodido/go-urn v1.4.0 // indirect
 	github.com/richardlehane/mscfb v1.0.6 // indirect
 	github.com/richardlehane/msoleps v1.0.6 // indirect
@@ -0,0 +1,217 @@
+package excel
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/cuishu/functools"
+	excelize "github.com/xuri/excelize/v2"
+)
+
+const (
+	// detectHeaderRows is the number of rows searched for the header by DetectHeader by default.
+	detectHeaderRows = 20
+	// detectHeaderThreshold is the least ratio of the fields a detected header must match.
+	detectHeaderThreshold = 0.5
+)
+
+// headerSeparator joins the parts of a column key in a multi-row header, e.g. "Address/City".
+const headerSeparator = "/"
+
+// HeaderRows sets the number of rows of the header. The column keys of a multi-row
+// header are composed from the header path, e.g. `xlsx:"Address/City"`.
+func (s *Sheet) HeaderRows(n int) *Sheet {
+	s.headerRows = n
+	return s
+}
+
+// DetectHeader searches the first 20 rows after the offset for the header instead of taking
+// the first one, the row matching most of the xlsx tags of the struct is chosen.
+func (s *Sheet) DetectHeader() *Sheet {
+	s.detectHeader = true
+	return s
+}
+
+// DetectHeaderRows is like DetectHeader but searches the first k rows after the offset.
+func (s *Sheet) DetectHeaderRows(k int) *Sheet {
+	s.detectRows = k
+	return s.DetectHeader()
+}
+
+// HeaderRow returns the 0-based index of the header row found by the last scan.
+func (s *Sheet) HeaderRow() int {
+	return s.headerRow
+}
+
+func (s *Sheet) headerRowCount() int {
+	if s.headerRows > 1 {
+		return s.headerRows
+	}
+	return 1
+}
+
+// headerKeys returns the column keys of the header starting at rows[start].
+func (s *Sheet) headerKeys(f *excelize.File, rows [][]string, start int) ([]string, error) {
+	n := s.headerRowCount()
+	if n == 1 {
+		return functools.Map(func(s string) string { return strings.TrimSpace(s) }, rows[start]), nil
+	}
+	header := rows[start : start+n]
+	if !s.fillMerged {
+		filled := make([][]string, start+n)
+		for i, row := range rows[:start+n] {
+			filled[i] = append([]string(nil), row...)
+		}
+		filled, err := s.fillMergedCells(f, filled)
+		if err != nil {
+			return nil, err
+		}
+		header = filled[start : start+n]
+	}
+	return composeHeader(header), nil
+}
+
+// composeHeader joins the cells of each column of a multi-row header, the parts
+// repeated by vertically merged cells are dropped.
+func composeHeader(header [][]string) []string {
+	width := 0
+	for _, row := range header {
+		width = max(width, len(row))
+	}
+	keys := make([]string, width)
+	for c := range width {
+		var parts []string
+		for _, row := range header {
+			if c >= len(row) {
+				continue
+			}
+			part := strings.TrimSpace(row[c])
+			if part == "" || (len(parts) > 0 && parts[len(parts)-1] == part) {
+				continue
+			}
+			parts = append(parts, part)
+		}
+		keys[c] = strings.Join(parts, headerSeparator)
+	}
+	return keys
+}
+
+type mergeRange struct {
+	top, bottom string
+}
+
+// headerLayout lays the titles out in n rows. Groups shared by adjacent columns are
+// merged horizontally, and a title with fewer parts than n is merged down to the last row.
+func headerLayout(titles []string, n int) ([][]string, []mergeRange) {
+	grid := make([][]string, n)
+	for r := range grid {
+		grid[r] = make([]string, len(titles))
+	}
+	leaf := make([]int, len(titles))
+	for c, title := range titles {
+		parts := strings.SplitN(title, headerSeparator, n)
+		for r, part := range parts {
+			grid[r][c] = part
+		}
+		leaf[c] = len(parts) - 1
+	}
+	var merges []mergeRange
+	for c := range titles {
+		if leaf[c] < n-1 {
+			merges = append(merges, mergeRange{cell(leaf[c]+1, c+1), cell(n, c+1)})
+		}
+	}
+	for r := 0; r < n-1; r++ {
+		for c := 0; c < len(titles); {
+			end := c
+			for end+1 < len(titles) && sameGroup(grid, leaf, r, c, end+1) {
+				end++
+			}
+			if end > c {
+				merges = append(merges, mergeRange{cell(r+1, c+1), cell(r+1, end+1)})
+			}
+			c = end + 1
+		}
+	}
+	return grid, merges
+}
+
+// sameGroup reports whether columns a and b share the group cell at row r.
+func sameGroup(grid [][]string, leaf []int, r, a, b int) bool {
+	if leaf[a] <= r || leaf[b] <= r {
+		return false
+	}
+	for i := 0; i <= r; i++ {
+		if grid[i][a] != grid[i][b] {
+			return false
+		}
+	}
+	return true
+}
+
+// findHeader returns the index of the first header row, which is the offset unless DetectHeader is set.
+// Without expected columns the first non-blank row is taken.
+func (s *Sheet) findHeader(f *excelize.File, rows [][]string, expected []string) (int, error) {
+	if !s.detectHeader {
+		return s.offset, nil
+	}
+	if len(expected) == 0 {
+		for i := s.offset; i < len(rows); i++ {
+			if !isBlankRow(rows[i]) {
+				return i, nil
+			}
+		}
+		return 0, fmt.Errorf("header not found in sheet %s", s.sheet)
+	}
+	best, bestScore := -1, 0
+	k := s.detectRows
+	if k <= 0 {
+		k = detectHeaderRows
+	}
+	last := min(len(rows)-s.headerRowCount(), s.offset+k-1)
+	for i := s.offset; i <= last; i++ {
+		keys, err := s.headerKeys(f, rows, i)
+		if err != nil {
+			return 0, err
+		}
+		if score := matchHeader(keys, expected); score > bestScore {
+			best, bestScore = i, score
+		}
+	}
+	if best < 0 {
+		return 0, fmt.Errorf("header not found in sheet %s: no row matches the columns %v", s.sheet, expected)
+	}
+	if float64(bestScore) < float64(len(expected))*detectHeaderThreshold {
+		return 0, fmt.Errorf("header not found in sheet %s: best row %d matches %d of %d columns", s.sheet, best+1, bestScore, len(expected))
+	}
+	return best, nil
+}
+
+// expectedColumns returns the columns a header of t must have.
+func (s *Sheet) expectedColumns(t reflect.Type) []string {
+	if !s.unpivot {
+		return titleRow(nil, t)
+	}
+	keys, _, _ := pivotFields(t)
+	names := make([]string, 0, len(keys))
+	for _, i := range keys {
+		names = append(names, getFieldName(t.Field(i)))
+	}
+	return names
+}
+
+// matchHeader counts the expected columns found in keys.
+func matchHeader(keys, expected []string) int {
+	found := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		found[key] = true
+	}
+	n := 0
+	for _, name := range expected {
+		if found[name] {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,75 @@
+package excel
+
+import (
+	"context"
+	"reflect"
+	"strings"
+
+	excelize "github.com/xuri/excelize/v2"
+)
+
+// scanKV reads a sheet laid out as label/value pairs in its first two columns
+// into the struct pointed to by rv, a label is matched by the xlsx tag.
+func (s *Sheet) scanKV(ctx context.Context, f *excelize.File, rv reflect.Value) error {
+	rows, err := s.loadRows(f)
+	if err != nil {
+		return err
+	}
+	if err := s.checkContext(ctx, 0); err != nil {
+		return err
+	}
+	data := make(map[string]string)
+	cells := make(map[string]string)
+	for i := s.offset; i < len(rows); i++ {
+		row := rows[i]
+		if len(row) == 0 {
+			continue
+		}
+		key := strings.TrimSpace(row[0])
+		if key == "" {
+			continue
+		}
+		if len(row) > 1 {
+			data[key] = strings.TrimSpace(row[1])
+		} else {
+			data[key] = ""
+		}
+		cells[key] = cell(s.area.top+i+1, s.area.left+2)
+	}
+	o, err := s.decodeStruct(f, rv.Type().Elem(), Row{ID: 1, Num: s.area.top + s.offset + 1, Data: data}, func(key string) string {
+		return cells[key]
+	})
+	if err != nil {
+		return err
+	}
+	rv.Elem().Set(o)
+	if len(s.errors) > 0 {
+		return s.errors[0]
+	}
+	return nil
+}
+
+// exportKV writes the struct pointed to by rv as label/value pairs in the first two columns.
+func (s *Sheet) exportKV(f *excelize.File, rv reflect.Value) error {
+	if err := s.initStyles(f); err != nil {
+		return err
+	}
+	index, err := f.NewSheet(s.sheet)
+	if err != nil {
+		return err
+	}
+	f.SetActiveSheet(index)
+	obj := rv.Elem()
+	labels := titleRow(s.filter, obj.Type())
+	for i, label := range labels {
+		f.SetCellStr(s.sheet, cell(i+1, 1), label)
+	}
+	if s.useTextStyle {
+		f.SetColStyle(s.sheet, "B", s.style)
+	}
+	i := 0
+	return s.exportRow(f, obj, func() string {
+		i++
+		return cell(i, 2)
+	})
+}
@@ -0,0 +1,176 @@
+package excel
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	excelize "github.com/xuri/excelize/v2"
+)
+
+// Headers returns the header of the sheet in column order, it is set by ScanMaps and the exports.
+func (s *Sheet) Headers() []string {
+	return s.title
+}
+
+// ScanMaps reads the rows of the sheet as maps keyed by the header, for sheets whose
+// columns are unknown at compile time. Headers returns the header in column order.
+func (s *Sheet) ScanMaps() ([]Row, error) {
+	return s.ScanMapsContext(context.Background())
+}
+
+// ScanMapsContext is like ScanMaps but stops reading when ctx is done.
+func (s *Sheet) ScanMapsContext(ctx context.Context) ([]Row, error) {
+	f, err := s.excelizeOpen()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	rows, err := s.loadRows(f)
+	if err != nil {
+		return nil, err
+	}
+	header := s.headerRowCount()
+	if len(rows) < s.offset+header {
+		return nil, fmt.Errorf("file rows less than %d", s.offset+header)
+	}
+	start, err := s.findHeader(f, rows, nil)
+	if err != nil {
+		return nil, err
+	}
+	s.headerRow = start
+	schema, err := s.headerKeys(f, rows, start)
+	if err != nil {
+		return nil, err
+	}
+	s.title = schema
+	rows = rows[start+header:]
+	result := make([]Row, 0, len(rows))
+	progress := s.newProgress(len(rows))
+	skipped, processed := 0, len(rows)
+	for i, row := range rows {
+		if err := s.checkContext(ctx, i); err != nil {
+			return nil, err
+		}
+		progress.report(i)
+		if s.stopRow(row) || (s.limit > 0 && len(result) >= s.limit) {
+			processed = i
+			break
+		}
+		if isBlankRow(row) {
+			continue
+		}
+		if skipped < s.skip {
+			skipped++
+			continue
+		}
+		data := make(map[string]string, len(schema))
+		for j, key := range schema {
+			if key == "" {
+				continue
+			}
+			if j < len(row) {
+				data[key] = strings.TrimSpace(row[j])
+			} else {
+				data[key] = ""
+			}
+		}
+		result = append(result, Row{ID: i + 1, Num: s.area.top + start + header + i + 1, Data: data})
+	}
+	progress.done(processed)
+	return result, nil
+}
+
+// ExportMaps exports rows to a bytes.Buffer, the columns are written in the order of headers.
+func (s *Sheet) ExportMaps(headers []string, rows []map[string]any) (*bytes.Buffer, error) {
+	f := excelize.NewFile()
+	defer f.Close()
+	style, err := f.NewStyle(&excelize.Style{
+		NumFmt: 49,
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.style = style
+	if s.sheet == "" {
+		s.sheet = defaultSheet
+	}
+	index, err := f.NewSheet(s.sheet)
+	if err != nil {
+		return nil, err
+	}
+	f.SetActiveSheet(index)
+	s.writeTitle(f, s.sheet, headers, cellGenerator(1))
+	progress := s.newProgress(len(rows))
+	for i, row := range rows {
+		progress.report(i)
+		col := cellGenerator(s.headerRowCount() + i + 1)
+		for _, header := range headers {
+			name := col()
+			v, ok := row[header]
+			if !ok || v == nil {
+				continue
+			}
+			if s.useTextStyle {
+				err = f.SetCellStr(s.sheet, name, toString(v))
+			} else {
+				err = f.SetCellValue(s.sheet, name, v)
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	progress.done(len(rows))
+	s.rowCnt = len(rows)
+	if s.sheet != defaultSheet {
+		f.DeleteSheet(defaultSheet)
+	}
+	return f.WriteToBuffer()
+}
+
+// StreamExportMaps is like ExportMaps but writes the rows with a stream writer.
+func (s *Sheet) StreamExportMaps(headers []string, rows []map[string]any) (*bytes.Buffer, error) {
+	f := excelize.NewFile()
+	defer f.Close()
+	if s.sheet == "" {
+		s.sheet = defaultSheet
+	}
+	index, err := f.NewSheet(s.sheet)
+	if err != nil {
+		return nil, err
+	}
+	f.SetActiveSheet(index)
+	writer, err := f.NewStreamWriter(s.sheet)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.streamWriteTitle(writer, headers); err != nil {
+		return nil, err
+	}
+	progress := s.newProgress(len(rows))
+	for i, row := range rows {
+		progress.report(i)
+		cells := make([]any, 0, len(headers))
+		for _, header := range headers {
+			v := row[header]
+			if s.useTextStyle && v != nil {
+				v = toString(v)
+			}
+			cells = append(cells, &excelize.Cell{StyleID: s.style, Value: v})
+		}
+		if err := writer.SetRow(cell(s.headerRowCount()+i+1, 1), cells); err != nil {
+			return nil, err
+		}
+	}
+	progress.done(len(rows))
+	s.rowCnt = len(rows)
+	if err := writer.Flush(); err != nil {
+		return nil, err
+	}
+	if s.sheet != defaultSheet {
+		f.DeleteSheet(defaultSheet)
+	}
+	return f.WriteToBuffer()
+}
@@ -0,0 +1,117 @@
+package excel
+
+import (
+	"encoding"
+	"reflect"
+
+	excelize "github.com/xuri/excelize/v2"
+)
+
+// Marshaler is implemented by types that write themselves to a cell as text.
+type Marshaler interface {
+	MarshalXLSX() ([]byte, error)
+}
+
+// Unmarshaler is implemented by types that read themselves from the text of a cell.
+type Unmarshaler interface {
+	UnmarshalXLSX(data []byte) error
+}
+
+// CellContext describes the cell a value is read from or written to.
+type CellContext struct {
+	Sheet string
+	// Cell is the reference of the cell, like "B2".
+	Cell string
+	// Raw is the value of the cell without its number format applied, it is empty on export.
+	Raw string
+	// NumFmt is the built-in number format id of the cell style.
+	NumFmt int
+	// NumFmtCode is the custom number format of the cell style, like `0.00 "USD"`, it is
+	// empty for a built-in format and on export.
+	NumFmtCode string
+	Date1904   bool
+}
+
+// CellMarshaler is like Marshaler but receives the cell being written and returns a full
+// Cell, so it can set the style and hyperlink. The hyperlink is not supported by stream export.
+type CellMarshaler interface {
+	MarshalXLSXCell(ctx CellContext) (Cell, error)
+}
+
+// CellUnmarshaler is like Unmarshaler but receives the cell being read along with its text.
+type CellUnmarshaler interface {
+	UnmarshalXLSXCell(ctx CellContext, value string) error
+}
+
+var (
+	marshalerType        = reflect.TypeOf((*Marshaler)(nil)).Elem()
+	unmarshalerType      = reflect.TypeOf((*Unmarshaler)(nil)).Elem()
+	cellMarshalerType    = reflect.TypeOf((*CellMarshaler)(nil)).Elem()
+	cellUnmarshalerType  = reflect.TypeOf((*CellUnmarshaler)(nil)).Elem()
+	textMarshalerType    = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	textUnmarshalerType  = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	exportInterfaceTypes = []reflect.Type{cellMarshalerType, marshalerType, textMarshalerType}
+	scanInterfaceTypes   = []reflect.Type{cellUnmarshalerType, unmarshalerType, textUnmarshalerType}
+)
+
+// implements reports whether t or *t implements any of ifaces.
+func implements(t reflect.Type, ifaces []reflect.Type) bool {
+	for _, iface := range ifaces {
+		if t.Implements(iface) || reflect.PointerTo(t).Implements(iface) {
+			return true
+		}
+	}
+	return false
+}
+
+// as returns field as a T, trying its address when only the pointer implements T.
+func as[T any](field reflect.Value) (T, bool) {
+	if field.CanInterface() {
+		if v, ok := field.Interface().(T); ok {
+			return v, true
+		}
+	}
+	if field.CanAddr() {
+		if v, ok := field.Addr().Interface().(T); ok {
+			return v, true
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+// cellContext returns the context of a cell being read, only Sheet and Date1904 are set without a cell.
+func (s *Sheet) cellContext(f *excelize.File, axis string) (CellContext, error) {
+	ctx := CellContext{Sheet: s.sheet, Cell: axis, Date1904: s.date1904}
+	if axis == "" {
+		return ctx, nil
+	}
+	origin := s.originCell(axis)
+	raw, err := f.GetCellValue(s.sheet, origin, excelize.Options{RawCellValue: true})
+	if err != nil {
+		return ctx, err
+	}
+	ctx.Raw = raw
+	id, err := f.GetCellStyle(s.sheet, origin)
+	if err != nil {
+		return ctx, err
+	}
+	style, err := f.GetStyle(id)
+	if err != nil {
+		return ctx, err
+	}
+	ctx.NumFmt = style.NumFmt
+	if style.CustomNumFmt != nil {
+		ctx.NumFmtCode = *style.CustomNumFmt
+	}
+	return ctx, nil
+}
+
+// exportContext returns the context of a cell being written.
+func (s *Sheet) exportContext(axis string) CellContext {
+	ctx := CellContext{Sheet: s.sheet, Cell: axis, Date1904: s.date1904}
+	if s.useTextStyle {
+		ctx.NumFmt = 49
+	}
+	return ctx
+}
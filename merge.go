@@ -0,0 +1,52 @@
+package excel
+
+import (
+	excelize "github.com/xuri/excelize/v2"
+)
+
+// fillMergedCells copies the value of every merged cell into all the cells it covers
+// inside the scanned area, and records the top-left cell of each covered cell so raw
+// values can be read from it.
+func (s *Sheet) fillMergedCells(f *excelize.File, rows [][]string) ([][]string, error) {
+	mergeCells, err := f.GetMergeCells(s.sheet)
+	if err != nil {
+		return nil, err
+	}
+	s.merged = make(map[string]string)
+	for _, mc := range mergeCells {
+		startCol, startRow, err := excelize.CellNameToCoordinates(mc.GetStartAxis())
+		if err != nil {
+			return nil, err
+		}
+		endCol, endRow, err := excelize.CellNameToCoordinates(mc.GetEndAxis())
+		if err != nil {
+			return nil, err
+		}
+		value := mc.GetCellValue()
+		for r := startRow; r <= endRow; r++ {
+			for c := startCol; c <= endCol; c++ {
+				if !s.area.contains(c, r) {
+					continue
+				}
+				x, y := r-s.area.top, c-s.area.left
+				for len(rows) < x {
+					rows = append(rows, nil)
+				}
+				for len(rows[x-1]) < y {
+					rows[x-1] = append(rows[x-1], "")
+				}
+				rows[x-1][y-1] = value
+				s.merged[cell(r, c)] = mc.GetStartAxis()
+			}
+		}
+	}
+	return rows, nil
+}
+
+// originCell returns the top-left cell of the merged cell covering name.
+func (s *Sheet) originCell(name string) string {
+	if origin, ok := s.merged[name]; ok {
+		return origin
+	}
+	return name
+}
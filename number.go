@@ -0,0 +1,94 @@
+package excel
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// RawCellValue reads the values of cells without their number format applied,
+// e.g. 0.125 instead of 12.5% and 1234.5 instead of 1,234.50.
+func (s *Sheet) RawCellValue() *Sheet {
+	s.rawValue = true
+	return s
+}
+
+// NumberFormat sets the decimal and grouping separators of numbers stored as text,
+// '.' and ',' by default, e.g. NumberFormat(',', '.') for 1.234,50.
+func (s *Sheet) NumberFormat(decimal, group rune) *Sheet {
+	s.decimal, s.group = decimal, group
+	return s
+}
+
+func isNumberKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// parseNumber parses a formatted number into a value of type t, ignoring spaces and currency
+// symbols. The grouping separator is only accepted between groups of 3 digits before the
+// decimal separator, so 1,5 is not read as 15. A percentage is divided by 100 for float
+// fields, and a number in parentheses is negative as in accounting formats.
+func (s *Sheet) parseNumber(text string, t reflect.Type) (reflect.Value, error) {
+	decimal, group := s.decimal, s.group
+	if decimal == 0 {
+		decimal = '.'
+	}
+	if group == 0 {
+		group = ','
+	}
+	text = strings.TrimSpace(text)
+	invalid := fmt.Errorf("invalid number %q", text)
+	body := text
+	var b strings.Builder
+	if len(body) > 2 && body[0] == '(' && body[len(body)-1] == ')' {
+		b.WriteByte('-')
+		body = body[1 : len(body)-1]
+	}
+	// run counts the digits since the last grouping separator
+	percent, signed, fraction, grouped, digits, run := false, false, false, false, 0, 0
+	for _, r := range body {
+		switch {
+		case r >= '0' && r <= '9':
+			b.WriteRune(r)
+			digits++
+			run++
+		case (r == '-' || r == '+') && !signed && digits == 0:
+			b.WriteRune(r)
+			signed = true
+		case r == decimal && !fraction:
+			if grouped && run != 3 {
+				return reflect.Value{}, invalid
+			}
+			b.WriteByte('.')
+			fraction = true
+		case r == group && !fraction:
+			if run == 0 || run > 3 || (grouped && run != 3) {
+				return reflect.Value{}, invalid
+			}
+			grouped, run = true, 0
+		case unicode.IsSpace(r), unicode.Is(unicode.Sc, r):
+		case r == '%' && !percent:
+			percent = true
+		default:
+			return reflect.Value{}, invalid
+		}
+	}
+	if digits == 0 || (grouped && !fraction && run != 3) {
+		return reflect.Value{}, invalid
+	}
+	number := b.String()
+	if percent {
+		if t.Kind() != reflect.Float32 && t.Kind() != reflect.Float64 {
+			return reflect.Value{}, fmt.Errorf("percentage %q needs a float field", text)
+		}
+		number += "e-2"
+	}
+	return getReflectValue(number, t)
+}
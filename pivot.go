@@ -0,0 +1,242 @@
+package excel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	excelize "github.com/xuri/excelize/v2"
+)
+
+// Unpivot reads a cross-tab sheet as records: the columns of the fields with the key option
+// are fixed, and every other column gives a record whose pivot field holds the column header
+// and whose value field holds the cell. Export pivots the records back into a matrix.
+//
+//	type Budget struct {
+//		Department string  `xlsx:"Department,key"`
+//		Month      string  `xlsx:"Month,pivot"`
+//		Amount     float64 `xlsx:"Amount,value"`
+//	}
+func (s *Sheet) Unpivot() *Sheet {
+	s.unpivot = true
+	return s
+}
+
+// pivotFields returns the indexes of the key fields, the pivot field and the value field of t.
+func pivotFields(t reflect.Type) (keys []int, pivot, value int) {
+	pivot, value = -1, -1
+	for i := 0; i < t.NumField(); i++ {
+		if ignored(t.Field(i)) {
+			continue
+		}
+		tag := parseTag(t.Field(i))
+		switch {
+		case tag.key:
+			keys = append(keys, i)
+		case tag.pivot:
+			pivot = i
+		case tag.value:
+			value = i
+		}
+	}
+	return keys, pivot, value
+}
+
+var errPivotFields = errors.New("unpivot needs a field with the pivot option and a field with the value option")
+
+// unpivotRows decodes every non-key cell of the rows after the header into a record.
+func (s *Sheet) unpivotRows(ctx context.Context, f *excelize.File, rows [][]string, start int, st reflect.Type) (reflect.Value, []Row, error) {
+	t := st.Elem()
+	keys, pivot, value := pivotFields(t)
+	if pivot < 0 || value < 0 {
+		return reflect.Value{}, nil, errPivotFields
+	}
+	pivotName, valueName := getFieldName(t.Field(pivot)), getFieldName(t.Field(value))
+	isKey := make(map[string]bool, len(keys))
+	for _, i := range keys {
+		isKey[getFieldName(t.Field(i))] = true
+	}
+	header := s.headerRowCount()
+	schema, err := s.headerKeys(f, rows, start)
+	if err != nil {
+		return reflect.Value{}, nil, err
+	}
+	columns := make(map[string]int, len(schema))
+	for j, key := range schema {
+		if _, ok := columns[key]; !ok {
+			columns[key] = j
+		}
+	}
+	rows = rows[start+header:]
+	items := reflect.MakeSlice(st, 0, len(rows))
+	var scanned []Row
+	progress := s.newProgress(len(rows))
+	n, skipped, processed := 0, 0, len(rows)
+	for i, row := range rows {
+		if err := s.checkContext(ctx, i); err != nil {
+			return reflect.Value{}, nil, err
+		}
+		progress.report(i)
+		if s.stopRow(row) || (s.limit > 0 && n >= s.limit) {
+			processed = i
+			break
+		}
+		if isBlankRow(row) {
+			continue
+		}
+		if skipped < s.skip {
+			skipped++
+			continue
+		}
+		n++
+		id := i + 1
+		rowNum := s.area.top + start + header + id
+		fixed := make(map[string]string, len(keys))
+		for j, name := range schema {
+			if isKey[name] && j < len(row) {
+				fixed[name] = strings.TrimSpace(row[j])
+			} else if _, ok := fixed[name]; isKey[name] && !ok {
+				fixed[name] = ""
+			}
+		}
+		for j, name := range schema {
+			if name == "" || isKey[name] || j >= len(row) {
+				continue
+			}
+			cellValue := strings.TrimSpace(row[j])
+			if cellValue == "" {
+				continue
+			}
+			data := make(map[string]string, len(fixed)+2)
+			for k, v := range fixed {
+				data[k] = v
+			}
+			data[pivotName], data[valueName] = name, cellValue
+			record := Row{ID: id, Num: rowNum, Data: data}
+			o, err := s.decodeStruct(f, t, record, func(key string) string {
+				switch key {
+				case pivotName:
+					return cell(s.area.top+start+header, s.area.left+j+1)
+				case valueName:
+					return cell(rowNum, s.area.left+j+1)
+				}
+				if k, ok := columns[key]; ok {
+					return cell(rowNum, s.area.left+k+1)
+				}
+				return ""
+			})
+			if err != nil {
+				return reflect.Value{}, nil, err
+			}
+			items = reflect.Append(items, o)
+			scanned = append(scanned, record)
+		}
+	}
+	progress.done(processed)
+	return items, scanned, nil
+}
+
+// pivotExport writes the records as a matrix, one row per distinct key and one
+// column per distinct pivot value in sorted order.
+func (s *Sheet) pivotExport(ctx context.Context, f *excelize.File, rv reflect.Value) error {
+	slice := rv.Elem()
+	t := slice.Type().Elem()
+	keys, pivot, value := pivotFields(t)
+	if pivot < 0 || value < 0 {
+		return errPivotFields
+	}
+	type group struct {
+		first  reflect.Value
+		values map[string]reflect.Value
+	}
+	var groups []*group
+	index := make(map[string]*group)
+	seen := make(map[string]bool)
+	var headers []string
+	n := slice.Len()
+	progress := s.newProgress(n)
+	for i := 0; i < n; i++ {
+		if err := s.checkContext(ctx, i); err != nil {
+			return err
+		}
+		progress.report(i)
+		obj := slice.Index(i)
+		parts := make([]string, 0, len(keys))
+		for _, k := range keys {
+			part, err := s.formatValue(obj.Field(k))
+			if err != nil {
+				return err
+			}
+			parts = append(parts, part)
+		}
+		key := strings.Join(parts, "\x00")
+		g, ok := index[key]
+		if !ok {
+			g = &group{first: obj, values: make(map[string]reflect.Value)}
+			index[key] = g
+			groups = append(groups, g)
+		}
+		header, err := s.formatValue(obj.Field(pivot))
+		if err != nil {
+			return err
+		}
+		if !seen[header] {
+			seen[header] = true
+			headers = append(headers, header)
+		}
+		if _, ok := g.values[header]; ok {
+			return fmt.Errorf("record %d repeats the keys %q and the pivot value %q of an earlier record",
+				i+1, strings.Join(parts, ", "), header)
+		}
+		g.values[header] = obj.Field(value)
+	}
+	sort.Strings(headers)
+
+	// the filter drops key columns, and all pivot columns when it drops the value field
+	var shown []int
+	for _, k := range keys {
+		if exported(t.Field(k)) && s.shown(t.Field(k)) {
+			shown = append(shown, k)
+		}
+	}
+	if !exported(t.Field(value)) || !s.shown(t.Field(value)) {
+		headers = nil
+	}
+
+	sheet, err := f.NewSheet(s.sheet)
+	if err != nil {
+		return err
+	}
+	f.SetActiveSheet(sheet)
+	col := cellGenerator(1)
+	for _, k := range shown {
+		f.SetCellStr(s.sheet, col(), getFieldName(t.Field(k)))
+	}
+	for _, header := range headers {
+		f.SetCellStr(s.sheet, col(), header)
+	}
+	for r, g := range groups {
+		col := cellGenerator(r + 2)
+		for _, k := range shown {
+			if err := s.exportField(f, g.first.Field(k), t.Field(k), col); err != nil {
+				return err
+			}
+		}
+		for _, header := range headers {
+			v, ok := g.values[header]
+			if !ok {
+				col()
+				continue
+			}
+			if err := s.exportField(f, v, t.Field(value), col); err != nil {
+				return err
+			}
+		}
+	}
+	progress.done(n)
+	s.rowCnt = len(groups)
+	return nil
+}
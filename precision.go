@@ -0,0 +1,105 @@
+package excel
+
+import (
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	excelize "github.com/xuri/excelize/v2"
+)
+
+// maxExactDigits is the number of significant digits excel keeps of a number.
+const maxExactDigits = 15
+
+// maxExactInt is the largest integer a float64, and so a numeric cell, holds exactly.
+const maxExactInt = 1 << 53
+
+var scientificNumber = regexp.MustCompile(`^[+-]?\d+(\.\d+)?[eE][+-]?\d+$`)
+
+// preciseValue returns the text of a numeric cell without the precision lost by its display,
+// e.g. 110101199001011000 for a General cell showing 1.10101E+17. It returns ErrPrecisionLoss
+// for an integer beyond the digits excel keeps, unless exact is false as for float fields.
+func (s *Sheet) preciseValue(f *excelize.File, axis, value string, exact bool) (string, error) {
+	scientific := strings.ContainsAny(value, "eE") && scientificNumber.MatchString(value)
+	if axis == "" || (!scientific && !(exact && integerDigits(value) > maxExactDigits)) {
+		return value, nil
+	}
+	origin := s.originCell(axis)
+	typ, err := f.GetCellType(s.sheet, origin)
+	if err != nil {
+		return value, err
+	}
+	if typ != excelize.CellTypeUnset && typ != excelize.CellTypeNumber {
+		// the text is what the user typed into a text cell
+		return value, nil
+	}
+	if scientific {
+		raw, err := f.GetCellValue(s.sheet, origin, excelize.Options{RawCellValue: true})
+		if err != nil {
+			return value, err
+		}
+		v, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return value, nil
+		}
+		value = strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	if exact && integerDigits(value) > maxExactDigits {
+		return value, ErrPrecisionLoss
+	}
+	return value, nil
+}
+
+// integerDigits returns the number of significant digits of an integer, or 0 if s is not one.
+func integerDigits(s string) int {
+	s = strings.TrimLeft(strings.TrimLeft(s, "+-"), "0")
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return 0
+		}
+	}
+	return len(s)
+}
+
+// looksNumeric reports whether excel would turn s into a number if it was typed into a cell.
+func looksNumeric(s string) bool {
+	s = strings.TrimLeft(s, "+-")
+	digits, dots := 0, 0
+	for _, r := range s {
+		switch {
+		case r >= '0' && r <= '9':
+			digits++
+		case r == '.':
+			dots++
+		default:
+			return false
+		}
+	}
+	return digits > 0 && dots <= 1
+}
+
+// keepText reports whether a field written as value needs the text style to stay exact:
+// strings that look numeric, and integers beyond what a numeric cell holds exactly.
+func keepText(field reflect.Value, value string) bool {
+	switch field.Kind() {
+	case reflect.String:
+		return looksNumeric(value)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v := field.Int()
+		return v > maxExactInt || v < -maxExactInt
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return field.Uint() > maxExactInt
+	}
+	return false
+}
+
+// initStyles creates the style used for the cells keepText reports and the styles of the serial types.
+func (s *Sheet) initStyles(f *excelize.File) error {
+	style, err := f.NewStyle(&excelize.Style{NumFmt: 49})
+	if err != nil {
+		return err
+	}
+	s.textStyle = style
+	return s.initSerialStyles(f)
+}
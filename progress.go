@@ -0,0 +1,56 @@
+package excel
+
+import "time"
+
+// Progress describes how far a scan or an export has gone in a sheet.
+type Progress struct {
+	Sheet   string
+	Rows    int
+	Total   int
+	Elapsed time.Duration
+}
+
+// ProgressFunc receives the progress of a scan or an export.
+type ProgressFunc func(Progress)
+
+type progressReporter struct {
+	sheet    string
+	fn       ProgressFunc
+	interval int
+	total    int
+	start    time.Time
+}
+
+func (s *Sheet) newProgress(total int) *progressReporter {
+	start := s.started
+	if start.IsZero() {
+		start = time.Now()
+	}
+	interval := s.progressInterval
+	if interval <= 0 {
+		interval = contextCheckRows
+	}
+	return &progressReporter{
+		sheet:    s.sheet,
+		fn:       s.progress,
+		interval: interval,
+		total:    total,
+		start:    start,
+	}
+}
+
+// report calls the callback every interval rows.
+func (p *progressReporter) report(rows int) {
+	if rows == 0 || rows%p.interval != 0 {
+		return
+	}
+	p.done(rows)
+}
+
+// done calls the callback regardless of the interval.
+func (p *progressReporter) done(rows int) {
+	if p.fn == nil {
+		return
+	}
+	p.fn(Progress{Sheet: p.sheet, Rows: rows, Total: p.total, Elapsed: time.Since(p.start)})
+}
@@ -0,0 +1,246 @@
+package excel
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	excelize "github.com/xuri/excelize/v2"
+)
+
+// Date is a calendar date without a time of day, like a birthday.
+// It is written as a date serial with the yyyy-mm-dd format.
+type Date struct {
+	Year  int
+	Month time.Month
+	Day   int
+}
+
+// DateOf returns the date of t in its location.
+func DateOf(t time.Time) Date {
+	y, m, d := t.Date()
+	return Date{y, m, d}
+}
+
+// In returns the time at the start of d in loc.
+func (d Date) In(loc *time.Location) time.Time {
+	return time.Date(d.Year, d.Month, d.Day, 0, 0, 0, 0, loc)
+}
+
+// IsZero reports whether d is the zero Date.
+func (d Date) IsZero() bool {
+	return d == Date{}
+}
+
+func (d Date) String() string {
+	return fmt.Sprintf("%04d-%02d-%02d", d.Year, d.Month, d.Day)
+}
+
+// MarshalText writes d as yyyy-mm-dd.
+func (d Date) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText reads d from yyyy-mm-dd.
+func (d *Date) UnmarshalText(data []byte) error {
+	t, err := time.Parse(time.DateOnly, string(data))
+	if err != nil {
+		return err
+	}
+	*d = DateOf(t)
+	return nil
+}
+
+// TimeOfDay is a wall clock time without a date, like the start of a shift.
+// It is written as the fraction of a day with the h:mm:ss format.
+type TimeOfDay struct {
+	Hour   int
+	Minute int
+	Second int
+}
+
+// TimeOfDayOf returns the time of day of t in its location.
+func TimeOfDayOf(t time.Time) TimeOfDay {
+	h, m, s := t.Clock()
+	return TimeOfDay{h, m, s}
+}
+
+func (t TimeOfDay) String() string {
+	return fmt.Sprintf("%02d:%02d:%02d", t.Hour, t.Minute, t.Second)
+}
+
+// MarshalText writes t as hh:mm:ss.
+func (t TimeOfDay) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// UnmarshalText reads t from hh:mm:ss or hh:mm.
+func (t *TimeOfDay) UnmarshalText(data []byte) error {
+	v, err := time.Parse(time.TimeOnly, string(data))
+	if err != nil {
+		if v, err = time.Parse("15:04", string(data)); err != nil {
+			return err
+		}
+	}
+	*t = TimeOfDayOf(v)
+	return nil
+}
+
+// seconds returns the number of seconds since midnight.
+func (t TimeOfDay) seconds() int {
+	return t.Hour*3600 + t.Minute*60 + t.Second
+}
+
+// Percent is a ratio written with the 0.00% format, so 0.125 is shown as 12.50%.
+// Scan reads both 12.5% and 0.125 as 0.125.
+type Percent float64
+
+var (
+	dateReflectType      = reflect.TypeOf(Date{})
+	timeOfDayReflectType = reflect.TypeOf(TimeOfDay{})
+	durationReflectType  = reflect.TypeOf(time.Duration(0))
+	percentReflectType   = reflect.TypeOf(Percent(0))
+)
+
+// dateNumFmt is the format of Date cells, the built-in date format depends on the locale.
+var dateNumFmt = "yyyy-mm-dd"
+
+// serialStyles are the styles the serial types are written with, a time.Duration is
+// written as a number of days with the [h]:mm:ss format.
+var serialStyles = map[reflect.Type]excelize.Style{
+	dateReflectType:      {CustomNumFmt: &dateNumFmt},
+	timeOfDayReflectType: {NumFmt: 21},
+	durationReflectType:  {NumFmt: 46},
+	percentReflectType:   {NumFmt: 10},
+}
+
+// isSerial reports whether t is written as a number with a style of serialStyles.
+func isSerial(t reflect.Type) bool {
+	_, ok := serialStyles[t]
+	return ok
+}
+
+// initSerialStyles creates the styles of serialStyles in f.
+func (s *Sheet) initSerialStyles(f *excelize.File) error {
+	s.serialStyles = make(map[reflect.Type]int, len(serialStyles))
+	for t, style := range serialStyles {
+		id, err := f.NewStyle(&style)
+		if err != nil {
+			return err
+		}
+		s.serialStyles[t] = id
+	}
+	return nil
+}
+
+// blankSerial reports whether field is a zero Date, which is left blank as it has no serial.
+func blankSerial(field reflect.Value) bool {
+	d, ok := field.Interface().(Date)
+	return ok && d.IsZero()
+}
+
+const secondsPerDay = 24 * 60 * 60
+
+// serialValue returns the number written for a field of a serial type.
+func (s *Sheet) serialValue(field reflect.Value) float64 {
+	switch v := field.Interface().(type) {
+	case Date:
+		return s.dateSerial(v)
+	case TimeOfDay:
+		return float64(v.seconds()) / secondsPerDay
+	case time.Duration:
+		return float64(v) / float64(24*time.Hour)
+	}
+	return field.Float()
+}
+
+// dateSerial returns the date serial of d, counting the 29 February 1900 excel believes in.
+func (s *Sheet) dateSerial(d Date) float64 {
+	epoch := time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+	if s.date1904 {
+		epoch = time.Date(1904, 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+	days := (d.In(time.UTC).Unix() - epoch.Unix()) / secondsPerDay
+	if !s.date1904 && days < 61 {
+		days--
+	}
+	return float64(days)
+}
+
+// decodeSerial sets a Date, TimeOfDay or time.Duration field from the raw value of its cell
+// when it is a number, and from its text otherwise.
+func (s *Sheet) decodeSerial(field reflect.Value, raw, text string, tag fieldTag) error {
+	switch field.Type() {
+	case dateReflectType:
+		t, err := s.parseTime(raw, text, tag)
+		if err != nil {
+			var d Date
+			if d.UnmarshalText([]byte(text)) != nil {
+				return err
+			}
+			t = d.In(time.UTC)
+		}
+		field.Set(reflect.ValueOf(DateOf(t)))
+	case timeOfDayReflectType:
+		t, err := parseTimeOfDay(raw, text, tag)
+		if err != nil {
+			return err
+		}
+		field.Set(reflect.ValueOf(t))
+	case durationReflectType:
+		d, err := parseDuration(raw, text)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(d))
+	}
+	return nil
+}
+
+// parseTimeOfDay parses a time of day from the fraction of a day, from text with the layouts
+// of the layout option, or from hh:mm:ss.
+func parseTimeOfDay(raw, text string, tag fieldTag) (TimeOfDay, error) {
+	var t TimeOfDay
+	if v, err := strconv.ParseFloat(raw, 64); err == nil {
+		seconds := int(math.Round((v-math.Floor(v))*secondsPerDay)) % secondsPerDay
+		return TimeOfDay{seconds / 3600, seconds / 60 % 60, seconds % 60}, nil
+	}
+	for _, layout := range tag.timeLayouts() {
+		if v, err := time.Parse(layout, text); err == nil {
+			return TimeOfDayOf(v), nil
+		}
+	}
+	err := t.UnmarshalText([]byte(text))
+	return t, err
+}
+
+// parseDuration parses a duration from a number of days, from text like 1h30m or from
+// a clock like 36:30:00 whose hours may exceed 24.
+func parseDuration(raw, text string) (time.Duration, error) {
+	if v, err := strconv.ParseFloat(raw, 64); err == nil {
+		return time.Duration(math.Round(v * float64(24*time.Hour))), nil
+	}
+	if d, err := time.ParseDuration(text); err == nil {
+		return d, nil
+	}
+	sign, clock := time.Duration(1), strings.TrimSpace(text)
+	if rest, ok := strings.CutPrefix(clock, "-"); ok {
+		sign, clock = -1, rest
+	}
+	parts := strings.Split(clock, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, fmt.Errorf("invalid duration %q", text)
+	}
+	var d time.Duration
+	for i, unit := range []time.Duration{time.Hour, time.Minute, time.Second}[:len(parts)] {
+		v, err := strconv.ParseFloat(strings.TrimSpace(parts[i]), 64)
+		if err != nil || (i > 0 && v >= 60) {
+			return 0, fmt.Errorf("invalid duration %q", text)
+		}
+		d += time.Duration(math.Round(v * float64(unit)))
+	}
+	return sign * d, nil
+}
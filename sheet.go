@@ -2,6 +2,8 @@ package excel
 
 import (
 	"bytes"
+	"context"
+	"encoding"
 	"errors"
 	"fmt"
 	_ "image/jpeg"
@@ -10,17 +12,25 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/cuishu/functools"
+	"github.com/go-playground/validator/v10"
 	excelize "github.com/xuri/excelize/v2"
 )
 
 const defaultSheet = "Sheet1"
 
+// contextCheckRows is the number of rows between two checks of the context.
+const contextCheckRows = 1000
+
 type Schema map[string]bool
 
 type Row struct {
-	ID   int
+	// ID is the 1-based index of the row after the header.
+	ID int
+	// Num is the 1-based number of the row in the sheet.
+	Num  int
 	Data map[string]string
 }
 
@@ -32,7 +42,15 @@ func (row Row) Get(key string) string {
 }
 
 type Error struct {
-	Row  Row
+	// Sheet is the name of the sheet the error was found in.
+	Sheet string
+	Row   Row
+	// Field is the column the error was found in, it is empty for errors of the whole row.
+	Field string
+	// Value is the text of the cell the error was found in.
+	Value string
+	// Err is the cause of the error if there is one, it can be matched with errors.Is.
+	Err  error
 	mesg string
 }
 
@@ -40,6 +58,15 @@ func (e Error) Error() string {
 	return e.mesg
 }
 
+func (e Error) Unwrap() error {
+	return e.Err
+}
+
+// fieldError records err for the column key of row.
+func (s *Sheet) fieldError(row Row, key string, err error) {
+	s.errors = append(s.errors, Error{Sheet: s.sheet, Row: row, Field: key, Value: row.Get(key), Err: err, mesg: fmt.Sprintf("%s: %s", key, err.Error())})
+}
+
 type Sheet struct {
 	filename      string
 	sheet         string
@@ -47,12 +74,44 @@ type Sheet struct {
 	errors        []Error
 	filter        Schema
 	offset        int
+	headerRows    int
+	headerRow     int
+	date1904      bool
+	detectHeader  bool
+	detectRows    int
+	stopAtBlank   bool
+	stopAt        func(string) bool
+	limit         int
+	skip          int
+	unpivot       bool
 	reader        io.Reader
 	style         int
 	rowCnt        int
 	colCnt        int
 	useTextStyle  bool
 	collectErrors bool
+	fillMerged    bool
+	rangeRef      string
+	definedName   string
+	table         string
+	area          area
+	merged        map[string]string
+	validate      *validator.Validate
+	locale        string
+	translation   *translation
+	converters    *Converters
+	sibling       func(name string) *Sheet
+	bools         boolWords
+	empty         EmptyPolicy
+	rawValue      bool
+	decimal       rune
+	group         rune
+	textStyle     int
+	serialStyles  map[reflect.Type]int
+
+	progress         ProgressFunc
+	progressInterval int
+	started          time.Time
 }
 
 // NewSheet creates a new Sheet.
@@ -82,7 +141,36 @@ func (s *Sheet) CollectErrors() *Sheet {
 	return s
 }
 
-// Offset sets the offset of the sheet.
+// FillMergedCells copies the value of a merged cell into every row and column it covers while scanning.
+func (s *Sheet) FillMergedCells() *Sheet {
+	s.fillMerged = true
+	return s
+}
+
+// Validator sets the validator used for the validate tag instead of the default one,
+// so custom validations can be registered on it.
+func (s *Sheet) Validator(v *validator.Validate) *Sheet {
+	s.validate = v
+	s.translate()
+	return s
+}
+
+// Locale sets the language of validation messages, "en" and "zh" are supported.
+func (s *Sheet) Locale(locale string) *Sheet {
+	s.locale = locale
+	s.translate()
+	return s
+}
+
+// OnProgress registers fn to be called every interval rows while scanning or exporting.
+// A non-positive interval means every 1000 rows.
+func (s *Sheet) OnProgress(interval int, fn ProgressFunc) *Sheet {
+	s.progressInterval = interval
+	s.progress = fn
+	return s
+}
+
+// Offset sets the number of rows above the header, use Skip to skip rows after the header.
 func (s *Sheet) Offset(n int) *Sheet {
 	s.offset = n
 	return s
@@ -102,38 +190,101 @@ func (s *Sheet) excelizeOpen() (*excelize.File, error) {
 	return nil, errors.New("filename can not be empty")
 }
 
-func (s *Sheet) scanSheet(f *excelize.File, rv reflect.Value) error {
-	props, err := f.GetWorkbookProps()
+func (s *Sheet) scanSheet(ctx context.Context, f *excelize.File, rv reflect.Value) error {
+	t := rv.Type().Elem().Elem()
+	rows, err := s.loadRows(f)
+	if err != nil {
+		return err
+	}
+	header := s.headerRowCount()
+	if len(rows) < s.offset+header {
+		return fmt.Errorf("file rows less than %d", s.offset+header)
+	}
+	start, err := s.findHeader(f, rows, s.expectedColumns(t))
+	if err != nil {
+		return err
+	}
+	s.headerRow = start
+	var items reflect.Value
+	var scanned []Row
+	if s.unpivot {
+		items, scanned, err = s.unpivotRows(ctx, f, rows, start, rv.Type().Elem())
+	} else {
+		items, scanned, err = s.decodeRows(ctx, f, rows, start, rv.Type().Elem())
+	}
 	if err != nil {
 		return err
 	}
-	var date1904 bool
-	if props.Date1904 != nil {
-		date1904 = *props.Date1904
+	rv.Elem().Set(items)
+	if err := s.checkConstraints(f, t, scanned); err != nil {
+		return err
+	}
+	if len(s.errors) > 0 {
+		return s.errors[0]
 	}
+	return nil
+}
 
-	t := rv.Type().Elem().Elem()
+// loadRows reads the rows of the scanned area of the sheet.
+func (s *Sheet) loadRows(f *excelize.File) ([][]string, error) {
+	props, err := f.GetWorkbookProps()
+	if err != nil {
+		return nil, err
+	}
+	s.date1904 = props.Date1904 != nil && *props.Date1904
+	if s.translation != nil && s.translation.err != nil {
+		return nil, s.translation.err
+	}
+	if err := s.resolveArea(f); err != nil {
+		return nil, err
+	}
+	rows, err := f.GetRows(s.sheet, excelize.Options{RawCellValue: s.rawValue})
+	if err != nil {
+		return nil, err
+	}
+	rows = s.area.crop(rows)
+	if s.fillMerged {
+		if rows, err = s.fillMergedCells(f, rows); err != nil {
+			return nil, err
+		}
+	}
+	return rows, nil
+}
 
-	rows, err := f.GetRows(s.sheet)
+// decodeRows decodes the rows after the header starting at rows[start] into a slice of type st,
+// it also returns the scanned rows for the checks across rows.
+func (s *Sheet) decodeRows(ctx context.Context, f *excelize.File, rows [][]string, start int, st reflect.Type) (reflect.Value, []Row, error) {
+	t := st.Elem()
+	header := s.headerRowCount()
+	schema, err := s.headerKeys(f, rows, start)
 	if err != nil {
-		return err
+		return reflect.Value{}, nil, err
 	}
-	var schema []string = make([]string, 0, t.NumField())
-	var length int = len(rows)
-	if length <= s.offset {
-		return fmt.Errorf("file rows less than %d", s.offset+1)
+	columns := make(map[string]int, len(schema))
+	for j, key := range schema {
+		if _, ok := columns[key]; !ok {
+			columns[key] = j
+		}
 	}
-	rows = rows[s.offset:]
-	length = len(rows)
-	array := reflect.MakeSlice(rv.Type().Elem(), length-1, length)
+	rows = rows[start+header:]
+	length := len(rows)
+	array := reflect.MakeSlice(st, length, length)
 	var indexArr []int = make([]int, 0, length)
-	n := 0
+	var scanned []Row = make([]Row, 0, length)
+	progress := s.newProgress(length)
+	n, skipped, processed := 0, 0, length
 	for i, row := range rows {
-		var obj map[string]string = make(map[string]string)
-		if i == 0 {
-			schema = append(schema, functools.Map(func(s string) string { return strings.TrimSpace(s) }, row)...)
-			continue
+		if err := s.checkContext(ctx, i); err != nil {
+			return reflect.Value{}, nil, err
+		}
+		progress.report(i)
+		if s.stopRow(row) || (s.limit > 0 && n >= s.limit) {
+			processed = i
+			break
 		}
+		id := i + 1
+		rowNum := s.area.top + start + header + id
+		var obj map[string]string = make(map[string]string)
 		for j, cell := range row {
 			value := strings.TrimSpace(cell)
 			if j >= len(schema) {
@@ -144,129 +295,222 @@ func (s *Sheet) scanSheet(f *excelize.File, rv reflect.Value) error {
 		if len(obj) == 0 {
 			continue
 		}
-		indexArr = append(indexArr, i-1)
+		if skipped < s.skip {
+			skipped++
+			continue
+		}
+		// the cells trimmed from the end of the row are blank, unlike the columns missing from the header
+		for _, key := range schema {
+			if _, ok := obj[key]; !ok {
+				obj[key] = ""
+			}
+		}
+		indexArr = append(indexArr, i)
+		record := Row{ID: id, Num: rowNum, Data: obj}
+		scanned = append(scanned, record)
 		n++
-		o := reflect.New(t)
-		for j := 0; j < t.NumField(); j++ {
-			if !s.collectErrors && len(s.errors) > 0 {
-				return s.errors[0]
+		o, err := s.decodeStruct(f, t, record, func(key string) string {
+			if j, ok := columns[key]; ok {
+				return cell(rowNum, s.area.left+j+1)
+			}
+			return ""
+		})
+		if err != nil {
+			return reflect.Value{}, nil, err
+		}
+		array.Index(i).Set(o)
+	}
+	progress.done(processed)
+	items := reflect.MakeSlice(st, n, n)
+	for i, index := range indexArr {
+		items.Index(i).Set(array.Index(index))
+	}
+	return items, scanned, nil
+}
+
+// decodeStruct decodes the values of row into a new value of type t, cellOf returns the name
+// of the cell holding the value of a key for the values read from the cell itself, or "" if
+// the key has no cell.
+func (s *Sheet) decodeStruct(f *excelize.File, t reflect.Type, row Row, cellOf func(key string) string) (reflect.Value, error) {
+	o := reflect.New(t).Elem()
+	for j := 0; j < t.NumField(); j++ {
+		if !s.collectErrors && len(s.errors) > 0 {
+			return reflect.Value{}, s.errors[0]
+		}
+		if ignored(t.Field(j)) {
+			continue
+		}
+		fieldTag := parseTag(t.Field(j))
+		if fieldTag.meta() {
+			value := s.sheet
+			if fieldTag.rownum {
+				value = strconv.Itoa(row.Num)
+			}
+			if rv, err := getReflectValue(value, t.Field(j).Type); err == nil && rv.IsValid() {
+				o.Field(j).Set(rv)
+			} else {
+				s.errors = append(s.errors, Error{Sheet: s.sheet, Row: row, Field: fieldTag.name, mesg: fmt.Sprintf("%s: can not hold %q", fieldTag.name, value)})
 			}
-			tag := getFieldName(t.Field(j))
-			valid := t.Field(j).Tag.Get("validate")
-			field := o.Elem().Field(j).Addr().Interface()
-			fieldType := reflect.TypeOf(field)
-			fieldValue := reflect.ValueOf(field)
-			value, ok := obj[tag]
-			if !ok {
+			continue
+		}
+		tag := fieldTag.name
+		valid := t.Field(j).Tag.Get("validate")
+		value, ok := row.Data[tag]
+		if !ok || value == "" {
+			def, err := s.emptyValue(fieldTag, valid, !ok)
+			if err != nil {
+				s.fieldError(row, tag, err)
 				continue
 			}
-			if fieldType.NumMethod() > 0 {
-				f, ok := fieldType.MethodByName("UnmarshalXLSX")
-				if !ok {
-					f, ok = fieldType.MethodByName("UnmarshalText")
-				}
-				if ok {
-					in := reflect.New(f.Type.In(1)).Elem()
-					in.SetBytes([]byte(value))
-					values := f.Func.Call([]reflect.Value{fieldValue, in})
-					if len(values) > 0 {
-						err := values[0].Interface()
-						if err != nil {
-							s.errors = append(s.errors, Error{Row: Row{ID: i, Data: obj}, mesg: fmt.Sprintf("%s: %s", tag, err.(error).Error())})
-							continue
-						}
-					}
-					goto validate
-				}
+			if def == "" && !ok {
+				continue
 			}
-			if rv, err := getReflectValue(value, fieldType.Elem()); err == nil {
-				if isTime(fieldType.Elem()) {
-					// styleID := s.timeStyle(f, rv)
-					for col, elem := range row {
-						if elem == value {
-							cellName, err := excelize.CoordinatesToCellName(col+1, i+s.offset+1)
-							if err != nil {
-								s.errors = append(s.errors, Error{Row: Row{ID: i, Data: obj}, mesg: fmt.Sprintf("%s: %s", tag, err.Error())})
-								continue
-							}
-							// f.SetCellStyle(s.Sheet, cellName, cellName, styleID)
-							value, err := f.GetCellValue(s.sheet, cellName, excelize.Options{RawCellValue: true})
-							if err != nil {
-								s.errors = append(s.errors, Error{Row: Row{ID: i, Data: obj}, mesg: fmt.Sprintf("%s: %s", tag, err.Error())})
-								continue
-							}
-							v, err := strconv.ParseFloat(value, 64)
-							if err != nil {
-								s.errors = append(s.errors, Error{Row: Row{ID: i, Data: obj}, mesg: fmt.Sprintf("%s: %s", tag, err.Error())})
-								continue
-							}
-							t, err := excelize.ExcelDateToTime(v, date1904)
-							if err != nil {
-								s.errors = append(s.errors, Error{Row: Row{ID: i, Data: obj}, mesg: fmt.Sprintf("%s: %s", tag, err.Error())})
-								continue
-							}
-							o.Elem().Field(j).Set(reflect.ValueOf(t))
-						}
-					}
-					goto validate
-				}
-				if fieldType.Elem() == picReflectType {
-					var pictures []Picture
-					var err error
-					pics, err := f.GetPictures(s.sheet, cell(i+1, j))
-					if err != nil {
-						s.errors = append(s.errors, Error{Row: Row{ID: i, Data: obj}, mesg: err.Error()})
-						continue
-					}
-					pictures = functools.Map(func(pic excelize.Picture) Picture {
-						return Picture{
-							File:     pic.File,
-							Format:   (*PicFormat)(pic.Format),
-							withPath: false,
-						}
-					}, pics)
-					rv = reflect.ValueOf(pictures)
-				}
-				o.Elem().Field(j).Set(rv)
-			} else {
-				s.errors = append(s.errors, Error{Row: Row{ID: i, Data: obj}, mesg: fmt.Sprintf("%s: %s", tag, err.Error())})
+			value = def
+		}
+		if len(fieldTag.enum) > 0 && value != "" {
+			if value, ok = fieldTag.enumValue(value); !ok {
+				s.fieldError(row, tag, fmt.Errorf("%w %q", ErrUnknownEnumLabel, row.Get(tag)))
 				continue
 			}
-		validate:
-			if valid != "" {
-				value := o.Elem().Field(j).Interface()
-				if value != nil {
-					if err := validate.Var(value, valid); err != nil {
-						s.errors = append(s.errors, Error{Row: Row{ID: i, Data: obj}, mesg: fmt.Sprintf("%s: %s", tag, err.Error())})
-						continue
-					}
-				}
+		}
+		// a picture has no text, so its cell is always read
+		if value != "" || t.Field(j).Type == picReflectType {
+			if err := s.decodeField(f, o.Field(j), fieldTag, value, cellOf(tag)); err != nil {
+				s.fieldError(row, tag, err)
+				continue
+			}
+		}
+		if valid != "" {
+			if err := s.validateVar(o.Field(j).Interface(), valid); err != nil {
+				s.fieldError(row, tag, err)
+				continue
 			}
 		}
-		array.Index(i - 1).Set(o.Elem())
 	}
-	items := reflect.MakeSlice(rv.Type().Elem(), n, n)
-	for i, index := range indexArr {
-		items.Index(i).Set(array.Index(index))
+	return o, nil
+}
+
+// decodeField decodes value, the text of the cell axis, into field. axis is "" for a value
+// without a cell, like the default of a missing column, which is decoded from its text only.
+func (s *Sheet) decodeField(f *excelize.File, field reflect.Value, tag fieldTag, value, axis string) error {
+	if decode := s.decoder(field.Type()); decode != nil {
+		rv, err := decode(value)
+		if err != nil {
+			return err
+		}
+		field.Set(rv)
+		return nil
 	}
-	rv.Elem().Set(items)
-	if len(s.errors) > 0 {
-		return s.errors[0]
+	if field.Kind() == reflect.Pointer {
+		p := reflect.New(field.Type().Elem())
+		if err := s.decodeField(f, p.Elem(), tag, value, axis); err != nil {
+			return err
+		}
+		field.Set(p)
+		return nil
 	}
+	ptr := field.Addr().Interface()
+	if u, ok := ptr.(CellUnmarshaler); ok {
+		ctx, err := s.cellContext(f, axis)
+		if err != nil {
+			return err
+		}
+		return u.UnmarshalXLSXCell(ctx, value)
+	}
+	if u, ok := ptr.(Unmarshaler); ok {
+		return u.UnmarshalXLSX([]byte(value))
+	}
+	switch {
+	case isTime(field.Type()), field.Type() == dateReflectType,
+		field.Type() == timeOfDayReflectType, field.Type() == durationReflectType:
+		var raw string
+		if axis != "" {
+			var err error
+			if raw, err = f.GetCellValue(s.sheet, s.originCell(axis), excelize.Options{RawCellValue: true}); err != nil {
+				return err
+			}
+		}
+		if !isTime(field.Type()) {
+			return s.decodeSerial(field, raw, value, tag)
+		}
+		t, err := s.parseTime(raw, value, tag)
+		if err != nil {
+			return err
+		}
+		field.Set(reflect.ValueOf(t))
+		return nil
+	case field.Type() == picReflectType:
+		if axis == "" {
+			return nil
+		}
+		pics, err := f.GetPictures(s.sheet, axis)
+		if err != nil {
+			return err
+		}
+		pictures := functools.Map(func(pic excelize.Picture) Picture {
+			return Picture{
+				File:     pic.File,
+				Format:   (*PicFormat)(pic.Format),
+				withPath: false,
+			}
+		}, pics)
+		if len(pictures) > 0 {
+			field.Set(reflect.ValueOf(pictures[0]))
+		}
+		return nil
+	case field.Type() == cellReflectType:
+		field.Set(reflect.ValueOf(Cell{Value: value}))
+		return nil
+	}
+	if u, ok := ptr.(encoding.TextUnmarshaler); ok {
+		return u.UnmarshalText([]byte(value))
+	}
+	if field.Kind() == reflect.Bool {
+		b, err := parseBoolWord(value, tag.bools, s.bools)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+		return nil
+	}
+	if field.Kind() == reflect.String || isNumberKind(field.Kind()) {
+		exact := field.Kind() != reflect.Float32 && field.Kind() != reflect.Float64
+		var err error
+		if value, err = s.preciseValue(f, axis, value, exact); err != nil {
+			return err
+		}
+	}
+	rv, err := getReflectValue(value, field.Type())
+	if err != nil && isNumberKind(field.Kind()) {
+		rv, err = s.parseNumber(value, field.Type())
+	}
+	if err != nil {
+		return err
+	}
+	if !rv.IsValid() {
+		return ErrUnsupportedFieldType
+	}
+	field.Set(rv)
 	return nil
 }
 
+// Scan reads the rows of the sheet into the slice pointed to by v.
 func (s *Sheet) Scan(v any) error {
-	rv := reflect.ValueOf(v)
-	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Type().Elem().Kind() != reflect.Slice {
-		panic("param must be slice pointer")
+	return s.ScanContext(context.Background(), v)
+}
+
+// ScanContext is like Scan but stops reading when ctx is done.
+func (s *Sheet) ScanContext(ctx context.Context, v any) error {
+	rv, err := s.checkSlice(v, opScan)
+	if err != nil {
+		return err
 	}
 	f, err := s.excelizeOpen()
 	if err != nil {
 		return err
 	}
 	defer f.Close()
-	return s.scanSheet(f, rv)
+	return s.scanSheet(ctx, f, rv)
 }
 
 type column func() string
@@ -287,6 +531,9 @@ func titleRow(schema Schema, t reflect.Type) []string {
 	var title []string = make([]string, 0, t.NumField())
 	for i := 0; i < t.NumField(); i++ {
 		field := t.Field(i)
+		if !exported(field) {
+			continue
+		}
 
 		tag := getFieldName(field)
 		show, ok := schema[tag]
@@ -298,25 +545,40 @@ func titleRow(schema Schema, t reflect.Type) []string {
 }
 
 func (s *Sheet) exportTitle(f *excelize.File, schema Schema, sheet string, t reflect.Type, col column) {
-	title := titleRow(schema, t)
+	s.writeTitle(f, sheet, titleRow(schema, t), col)
+}
+
+func (s *Sheet) writeTitle(f *excelize.File, sheet string, title []string, col column) {
 	s.title = title
 	s.colCnt = len(title)
 	if s.useTextStyle {
 		f.SetColStyle(sheet, "A:"+toTwentySix(s.colCnt), s.style)
 	}
-	for _, v := range title {
-		f.SetCellStr(sheet, col(), v)
+	if s.headerRowCount() == 1 {
+		for _, v := range title {
+			f.SetCellStr(sheet, col(), v)
+		}
+		return
+	}
+	grid, merges := headerLayout(title, s.headerRowCount())
+	for r, row := range grid {
+		for c, v := range row {
+			f.SetCellStr(sheet, cell(r+1, c+1), v)
+		}
+	}
+	for _, m := range merges {
+		f.MergeCell(sheet, m.top, m.bottom)
 	}
 }
 
-func (s *Sheet) exportPic(f *excelize.File, field reflect.Value, col column) error {
+func (s *Sheet) exportPic(f *excelize.File, field reflect.Value, axis string) error {
 	pic := field.Interface().(Picture)
 	if pic.withPath {
-		if err := f.AddPicture(s.sheet, col(), pic.Name, (*excelize.GraphicOptions)(pic.Format)); err != nil {
+		if err := f.AddPicture(s.sheet, axis, pic.Name, (*excelize.GraphicOptions)(pic.Format)); err != nil {
 			return err
 		}
 	} else {
-		if err := f.AddPictureFromBytes(s.sheet, col(),
+		if err := f.AddPictureFromBytes(s.sheet, axis,
 			&excelize.Picture{
 				File:   pic.File,
 				Format: (*excelize.GraphicOptions)(pic.Format),
@@ -327,107 +589,167 @@ func (s *Sheet) exportPic(f *excelize.File, field reflect.Value, col column) err
 	return nil
 }
 
-func (s *Sheet) exportCell(f *excelize.File, field reflect.Value, col column) error {
-	c := field.Interface().(Cell)
+// writeCell writes the value of c to axis along with its hyperlink and style.
+func (s *Sheet) writeCell(f *excelize.File, axis string, c Cell) error {
+	if err := f.SetCellStr(s.sheet, axis, c.Value); err != nil {
+		return err
+	}
 	if c.HyperLink.Link != "" {
-		column := col()
-		f.SetCellStr(s.sheet, column, c.Value)
-		f.SetCellHyperLink(s.sheet, column, c.HyperLink.Link, string(c.HyperLink.Type))
-		if c.Style != nil {
-			style, err := f.NewStyle(c.Style)
-			if err != nil {
-				return err
-			}
-			if err := f.SetCellStyle(s.sheet, column, column, style); err != nil {
-				return err
-			}
+		if err := f.SetCellHyperLink(s.sheet, axis, c.HyperLink.Link, string(c.HyperLink.Type)); err != nil {
+			return err
+		}
+	}
+	if c.Style != nil {
+		style, err := f.NewStyle(c.Style)
+		if err != nil {
+			return err
+		}
+		if err := f.SetCellStyle(s.sheet, axis, axis, style); err != nil {
+			return err
 		}
 	}
 	return nil
 }
 
-func (s *Sheet) exportStruct(f *excelize.File, field reflect.Value, col column) error {
-	if field.Type() == picReflectType {
-		return s.exportPic(f, field, col)
-	} else if field.Type() == cellReflectType {
-		return s.exportCell(f, field, col)
+func (s *Sheet) exportRow(f *excelize.File, obj reflect.Value, col column) error {
+	t := obj.Type()
+	for i := 0; i < obj.NumField(); i++ {
+		if err := s.exportField(f, obj.Field(i), t.Field(i), col); err != nil {
+			return err
+		}
 	}
-	fun, ok := field.Type().MethodByName("MarshalXLSX")
-	if !ok {
-		fun, ok = field.Type().MethodByName("MarshalText")
+	return nil
+}
+
+// shown reports whether the column of a field passes the filter.
+func (s *Sheet) shown(sf reflect.StructField) bool {
+	show, ok := s.filter[getFieldName(sf)]
+	return len(s.filter) == 0 || (show && ok)
+}
+
+// exportField writes a field of a row to the next cell given by col.
+func (s *Sheet) exportField(f *excelize.File, field reflect.Value, sf reflect.StructField, col column) error {
+	if !exported(sf) || !s.shown(sf) {
+		return nil
 	}
-	if ok {
-		res := fun.Func.Call([]reflect.Value{field})
-		if res[1].Interface() != nil {
-			err, ok := res[1].Interface().(error)
-			if !ok {
-				return fmt.Errorf("%s has invalid return type", fun.Name)
-			}
+	axis := col()
+	for field.Kind() == reflect.Pointer && s.encoder(field.Type()) == nil {
+		if field.IsNil() {
+			return nil
+		}
+		field = field.Elem()
+	}
+	tag := parseTag(sf)
+	if len(tag.enum) > 0 {
+		value, err := s.formatValue(field)
+		if err != nil {
 			return err
 		}
-		f.SetCellStr(s.sheet, col(), toString(res[0].Interface()))
-	} else if isTime(field.Type()) {
-		f.SetCellValue(s.sheet, col(), field.Interface())
-	} else {
-		panic("struct type must implement MarshalXLSX or MarshalText")
+		return f.SetCellStr(s.sheet, axis, tag.enumLabel(value))
+	}
+	if encode := s.encoder(field.Type()); encode != nil {
+		value, err := encode(field)
+		if err != nil {
+			return err
+		}
+		return f.SetCellValue(s.sheet, axis, value)
+	}
+	if m, ok := as[CellMarshaler](field); ok {
+		c, err := m.MarshalXLSXCell(s.exportContext(axis))
+		if err != nil {
+			return err
+		}
+		return s.writeCell(f, axis, c)
+	}
+	switch {
+	case field.Type() == picReflectType:
+		return s.exportPic(f, field, axis)
+	case field.Type() == cellReflectType:
+		return s.writeCell(f, axis, field.Interface().(Cell))
+	case isSerial(field.Type()):
+		if blankSerial(field) {
+			return nil
+		}
+		if err := f.SetCellFloat(s.sheet, axis, s.serialValue(field), -1, 64); err != nil {
+			return err
+		}
+		return f.SetCellStyle(s.sheet, axis, axis, s.serialStyles[field.Type()])
+	case isTime(field.Type()):
+		value, err := timeValue(field.Interface().(time.Time), tag)
+		if err != nil {
+			return err
+		}
+		return f.SetCellValue(s.sheet, axis, value)
+	case field.Kind() == reflect.Bool && !implements(field.Type(), exportInterfaceTypes):
+		return f.SetCellStr(s.sheet, axis, s.boolText(field.Bool(), tag))
+	}
+	value, err := s.formatValue(field)
+	if err != nil {
+		return err
+	}
+	if err := f.SetCellStr(s.sheet, axis, value); err != nil {
+		return err
+	}
+	if keepText(field, value) {
+		return f.SetCellStyle(s.sheet, axis, axis, s.textStyle)
 	}
 	return nil
 }
 
-func (s *Sheet) exportRow(f *excelize.File, obj reflect.Value, col column) error {
-	t := obj.Type()
-	for i := 0; i < obj.NumField(); i++ {
-		field := obj.Field(i)
-		if field.Kind() == reflect.Struct {
-			if err := s.exportStruct(f, field, col); err != nil {
-				return err
-			}
-		} else {
-			tag := getFieldName(t.Field(i))
-			show, ok := s.filter[tag]
-			if (len(s.filter) == 0) || (show && ok) {
-				if field.NumMethod() > 0 {
-					fun, ok := field.Type().MethodByName("MarshalXLSX")
-					if !ok {
-						fun, ok = field.Type().MethodByName("MarshalText")
-					}
-					if ok {
-						res := fun.Func.Call([]reflect.Value{field})
-						if res[1].Interface() != nil {
-							err, ok := res[1].Interface().(error)
-							if !ok {
-								return fmt.Errorf("%s has invalid return type", fun.Name)
-							}
-							return err
-						}
-						colIdx := col()
-						f.SetCellStr(s.sheet, colIdx, toString(res[0].Interface()))
-						continue
-					}
-				}
-				colIdx := col()
-				f.SetCellStr(s.sheet, colIdx, toString(field.Interface()))
-			}
+// formatValue returns the text of a field, using its converter, Marshaler or encoding.TextMarshaler
+// if there is one.
+func (s *Sheet) formatValue(field reflect.Value) (string, error) {
+	if encode := s.encoder(field.Type()); encode != nil {
+		value, err := encode(field)
+		if err != nil {
+			return "", err
 		}
+		return toString(value), nil
 	}
-	return nil
+	if field.Kind() == reflect.Pointer && field.IsNil() {
+		return "", nil
+	}
+	if m, ok := as[Marshaler](field); ok {
+		data, err := m.MarshalXLSX()
+		return string(data), err
+	}
+	if m, ok := as[encoding.TextMarshaler](field); ok {
+		data, err := m.MarshalText()
+		return string(data), err
+	}
+	if field.Kind() == reflect.Struct {
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedFieldType, field.Type())
+	}
+	return toString(field.Interface()), nil
 }
 
-func (s *Sheet) exportRows(f *excelize.File, slice reflect.Value) error {
-	rowNum := 1
+func (s *Sheet) exportRows(ctx context.Context, f *excelize.File, slice reflect.Value) error {
+	rowNum := s.headerRowCount()
 	n := slice.Len()
+	progress := s.newProgress(n)
 	for i := 0; i < n; i++ {
+		if err := s.checkContext(ctx, i); err != nil {
+			return err
+		}
+		progress.report(i)
 		rowNum++
 		obj := slice.Index(i)
 		if err := s.exportRow(f, obj, cellGenerator(rowNum)); err != nil {
 			return err
 		}
 	}
+	progress.done(n)
 	s.rowCnt = n
 	return nil
 }
 
-func (s *Sheet) sheetExport(f *excelize.File, rv reflect.Value) error {
+func (s *Sheet) sheetExport(ctx context.Context, f *excelize.File, rv reflect.Value) error {
+	if err := s.initStyles(f); err != nil {
+		return err
+	}
+	if s.unpivot {
+		return s.pivotExport(ctx, f, rv)
+	}
 	t := rv.Type().Elem().Elem()
 
 	sheet, err := f.NewSheet(s.sheet)
@@ -440,22 +762,18 @@ func (s *Sheet) sheetExport(f *excelize.File, rv reflect.Value) error {
 
 	slice := rv.Elem()
 
-	if err := s.exportRows(f, slice); err != nil {
+	if err := s.exportRows(ctx, f, slice); err != nil {
 		return err
 	}
 
-	return nil
+	return s.addEnumValidations(f, t)
 }
 
-func (s *Sheet) export(f *excelize.File, v any) error {
+func (s *Sheet) export(ctx context.Context, f *excelize.File, rv reflect.Value) error {
 	if s.sheet == "" {
 		s.sheet = defaultSheet
 	}
-	rv := reflect.ValueOf(v)
-	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Type().Elem().Kind() != reflect.Slice {
-		panic("param must be slice ptr")
-	}
-	if err := s.sheetExport(f, rv); err != nil {
+	if err := s.sheetExport(ctx, f, rv); err != nil {
 		return err
 	}
 	if s.sheet != defaultSheet {
@@ -466,6 +784,15 @@ func (s *Sheet) export(f *excelize.File, v any) error {
 
 // Export exports the sheet to a bytes.Buffer.
 func (s *Sheet) Export(v any) (*bytes.Buffer, error) {
+	return s.ExportContext(context.Background(), v)
+}
+
+// ExportContext is like Export but stops writing when ctx is done.
+func (s *Sheet) ExportContext(ctx context.Context, v any) (*bytes.Buffer, error) {
+	rv, err := s.checkSlice(v, opExport)
+	if err != nil {
+		return nil, err
+	}
 	f := excelize.NewFile()
 	defer f.Close()
 	style, err := f.NewStyle(&excelize.Style{
@@ -475,7 +802,7 @@ func (s *Sheet) Export(v any) (*bytes.Buffer, error) {
 		return nil, err
 	}
 	s.style = style
-	if err := s.export(f, v); err != nil {
+	if err := s.export(ctx, f, rv); err != nil {
 		return nil, err
 	}
 	return f.WriteToBuffer()
@@ -483,6 +810,10 @@ func (s *Sheet) Export(v any) (*bytes.Buffer, error) {
 
 // ExportTo exports the sheet to a io.Writer.
 func (s *Sheet) ExportTo(w io.Writer, v any) error {
+	rv, err := s.checkSlice(v, opExport)
+	if err != nil {
+		return err
+	}
 	f := excelize.NewFile()
 	defer f.Close()
 	style, err := f.NewStyle(&excelize.Style{
@@ -492,13 +823,25 @@ func (s *Sheet) ExportTo(w io.Writer, v any) error {
 		return err
 	}
 	s.style = style
-	if err := s.export(f, v); err != nil {
+	if err := s.export(context.Background(), f, rv); err != nil {
 		return err
 	}
 	_, err = f.WriteTo(w)
 	return err
 }
 
+// checkContext returns the error of ctx with the row reached once ctx is done,
+// it is checked every contextCheckRows rows.
+func (s *Sheet) checkContext(ctx context.Context, row int) error {
+	if row%contextCheckRows != 0 {
+		return nil
+	}
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("sheet %s stopped at row %d: %w", s.sheet, row, err)
+	}
+	return nil
+}
+
 // Filter sets the filter of the sheet.
 func (s *Sheet) Filter(schema Schema) *Sheet {
 	s.filter = schema
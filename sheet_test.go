@@ -1,14 +1,23 @@
 package excel
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
+	"math"
 	"os"
+	"reflect"
+	"regexp"
+	"runtime"
 	"strconv"
 	"strings"
+	"sync"
 	"testing"
 	"time"
+	"weak"
 
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
 	excelize "github.com/xuri/excelize/v2"
 )
 
@@ -187,3 +196,1244 @@ func TestTime(t *testing.T) {
 	os.WriteFile("b.xlsx", data.Bytes(), 0644)
 	t.Fail()
 }
+
+type TestValidateObject struct {
+	Name  string `xlsx:"name" validate:"required"`
+	Phone string `xlsx:"phone" validate:"phone_cn"`
+}
+
+func TestValidatorAndLocale(t *testing.T) {
+	buff, err := NewSheet("Sheet1").Export(&[]TestValidateObject{
+		{"Smith", "13800138000"},
+		{"", "110"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := validator.New()
+	v.RegisterValidation("phone_cn", func(fl validator.FieldLevel) bool {
+		return len(fl.Field().String()) == 11
+	})
+	var data []TestValidateObject
+	sheet := NewSheetFromReader(bytes.NewReader(buff.Bytes()), "Sheet1").Validator(v).Locale("zh").CollectErrors()
+	if err := sheet.Scan(&data); err == nil {
+		t.Fatal("expected validation error")
+	}
+	errs := sheet.Errors()
+	if len(errs) != 2 {
+		t.Fatalf("expected 2 errors, got %v", errs)
+	}
+	if errs[0].Error() != "name: 为必填字段" {
+		t.Errorf("unexpected message %q", errs[0].Error())
+	}
+	if !strings.HasPrefix(errs[1].Error(), "phone: ") {
+		t.Errorf("unexpected message %q", errs[1].Error())
+	}
+
+	sheet = NewSheetFromReader(bytes.NewReader(buff.Bytes()), "Sheet1").Validator(v).Locale("xx")
+	if err := sheet.Scan(&data); err == nil {
+		t.Fatal("expected unsupported locale error")
+	}
+}
+
+func TestLocaleValidators(t *testing.T) {
+	buff, err := NewSheet("Sheet1").Export(&[]TestValidateObject{{"", "13800138000"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	phone := func(fl validator.FieldLevel) bool { return len(fl.Field().String()) == 11 }
+	v1, v2 := validator.New(), validator.New()
+	v1.RegisterValidation("phone_cn", phone)
+	v2.RegisterValidation("phone_cn", phone)
+	var wg sync.WaitGroup
+	for _, v := range []*validator.Validate{v1, v2, v1, v2} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var data []TestValidateObject
+			err := NewSheetFromReader(bytes.NewReader(buff.Bytes()), "Sheet1").Validator(v).Locale("zh").Scan(&data)
+			if err == nil || err.Error() != "name: 为必填字段" {
+				t.Errorf("unexpected error %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	var data []TestValidateObject
+	trans, err := Translator(v2, "zh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	v2.RegisterTranslation("phone_cn", trans, func(ut ut.Translator) error {
+		return ut.Add("phone_cn", "{0}不是手机号", true)
+	}, func(ut ut.Translator, fe validator.FieldError) string {
+		msg, _ := ut.T("phone_cn", fe.Field())
+		return msg
+	})
+	buff, _ = NewSheet("Sheet1").Export(&[]TestValidateObject{{"a", "110"}})
+	err = NewSheetFromReader(buff, "Sheet1").Validator(v2).Locale("zh").Scan(&data)
+	if err == nil || !strings.Contains(err.Error(), "不是手机号") {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestTranslatorRelease(t *testing.T) {
+	v := validator.New()
+	if _, err := Translator(v, "zh"); err != nil {
+		t.Fatal(err)
+	}
+	key := translationKey{validate: weak.Make(v), locale: "zh"}
+	v = nil
+	for range 100 {
+		if _, ok := translations.Load(key); !ok {
+			return
+		}
+		runtime.GC()
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Errorf("the translation of a collected validator is kept")
+}
+
+func TestProgress(t *testing.T) {
+	humans := make([]Human, 2500)
+	for i := range humans {
+		humans[i] = Human{i, fmt.Sprintf("name_%d", i)}
+	}
+	var rows []int
+	record := func(p Progress) {
+		if p.Sheet != "Human" || p.Total != len(humans) {
+			t.Errorf("unexpected progress %+v", p)
+		}
+		rows = append(rows, p.Rows)
+	}
+	buff, err := NewSheet("Human").OnProgress(1000, record).Export(&humans)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(rows) != "[1000 2000 2500]" {
+		t.Errorf("unexpected export progress %v", rows)
+	}
+	rows = nil
+	var data []Human
+	if err := NewSheetFromReader(buff, "Human").OnProgress(1000, record).Scan(&data); err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(rows) != "[1000 2000 2500]" {
+		t.Errorf("unexpected scan progress %v", rows)
+	}
+}
+
+type TestMergedObject struct {
+	Department string `xlsx:"department"`
+	Name       string `xlsx:"name"`
+}
+
+func TestFillMergedCells(t *testing.T) {
+	f := excelize.NewFile()
+	defer f.Close()
+	f.SetSheetRow("Sheet1", "A1", &[]string{"department", "name"})
+	f.SetSheetRow("Sheet1", "A2", &[]string{"Sales", "Smith"})
+	f.SetSheetRow("Sheet1", "B3", &[]string{"Jack"})
+	f.SetSheetRow("Sheet1", "B4", &[]string{"James"})
+	if err := f.MergeCell("Sheet1", "A2", "A4"); err != nil {
+		t.Fatal(err)
+	}
+	buff, err := f.WriteToBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var data []TestMergedObject
+	if err := NewSheetFromReader(buff, "Sheet1").FillMergedCells().Scan(&data); err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != 3 {
+		t.Fatalf("expected 3 rows, got %v", data)
+	}
+	for _, item := range data {
+		if item.Department != "Sales" {
+			t.Errorf("unexpected department in %+v", item)
+		}
+	}
+}
+
+type TestGroupedObject struct {
+	Name   string `xlsx:"name"`
+	City   string `xlsx:"Address/City"`
+	Street string `xlsx:"Address/Street"`
+}
+
+func TestMultiRowHeader(t *testing.T) {
+	objs := []TestGroupedObject{{"Smith", "Beijing", "Chang'an"}, {"Jack", "Shanghai", "Nanjing Road"}}
+	for _, stream := range []bool{false, true} {
+		sheet := NewSheet("Sheet1").HeaderRows(2)
+		var buff *bytes.Buffer
+		var err error
+		if stream {
+			buff, err = sheet.StreamExport(&objs)
+		} else {
+			buff, err = sheet.Export(&objs)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		f, err := excelize.OpenReader(bytes.NewReader(buff.Bytes()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		merges, err := f.GetMergeCells("Sheet1")
+		f.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ranges []string
+		for _, m := range merges {
+			ranges = append(ranges, m.GetStartAxis()+":"+m.GetEndAxis())
+		}
+		if strings.Join(ranges, ",") != "A1:A2,B1:C1" {
+			t.Errorf("unexpected merged cells %v", ranges)
+		}
+		var data []TestGroupedObject
+		if err := NewSheetFromReader(buff, "Sheet1").HeaderRows(2).Scan(&data); err != nil {
+			t.Fatal(err)
+		}
+		if fmt.Sprint(data) != fmt.Sprint(objs) {
+			t.Errorf("unexpected data %v", data)
+		}
+	}
+}
+
+func TestDetectHeader(t *testing.T) {
+	f := excelize.NewFile()
+	defer f.Close()
+	f.SetSheetRow("Sheet1", "A1", &[]string{"Employee Report"})
+	f.SetSheetRow("Sheet1", "A2", &[]string{"generated at 2026-10-18"})
+	f.SetSheetRow("Sheet1", "A4", &[]string{"id", "name", "note"})
+	f.SetSheetRow("Sheet1", "A5", &[]any{1, "Smith"})
+	buff, err := f.WriteToBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var data []Human
+	sheet := NewSheetFromReader(bytes.NewReader(buff.Bytes()), "Sheet1").DetectHeader()
+	if err := sheet.Scan(&data); err != nil {
+		t.Fatal(err)
+	}
+	if sheet.HeaderRow() != 3 || len(data) != 1 || data[0].Name != "Smith" {
+		t.Errorf("unexpected header row %d and data %v", sheet.HeaderRow(), data)
+	}
+
+	var objs []TestGroupedObject
+	if err := NewSheetFromReader(bytes.NewReader(buff.Bytes()), "Sheet1").DetectHeader().Scan(&objs); err == nil {
+		t.Error("expected header not found error")
+	}
+}
+
+func TestStopConditions(t *testing.T) {
+	f := excelize.NewFile()
+	defer f.Close()
+	f.SetSheetRow("Sheet1", "A1", &[]string{"id", "name"})
+	f.SetSheetRow("Sheet1", "A2", &[]any{1, "Smith"})
+	f.SetSheetRow("Sheet1", "A3", &[]any{2, "Jack"})
+	f.SetSheetRow("Sheet1", "A4", &[]any{3, "James"})
+	f.SetSheetRow("Sheet1", "A5", &[]any{"合计", 3})
+	f.SetSheetRow("Sheet1", "A7", &[]string{"signed by", "Smith"})
+	buff, err := f.WriteToBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		sheet func(*Sheet) *Sheet
+		n     int
+	}{
+		{func(s *Sheet) *Sheet { return s.StopAtRegexp(regexp.MustCompile(`^合计|^Total`)) }, 3},
+		{func(s *Sheet) *Sheet { return s.StopAtBlankRow() }, 0},
+		{func(s *Sheet) *Sheet { return s.StopAtRegexp(regexp.MustCompile(`^合计`)).Limit(2) }, 2},
+	}
+	for i, c := range cases {
+		var data []Human
+		err := c.sheet(NewSheetFromReader(bytes.NewReader(buff.Bytes()), "Sheet1")).Scan(&data)
+		if c.n == 0 {
+			if err == nil {
+				t.Errorf("case %d: expected parse error of the footer", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("case %d: %v", i, err)
+		}
+		if len(data) != c.n {
+			t.Errorf("case %d: expected %d rows, got %v", i, c.n, data)
+		}
+	}
+}
+
+func TestPaging(t *testing.T) {
+	humans := make([]Human, 10)
+	for i := range humans {
+		humans[i] = Human{i + 1, fmt.Sprintf("name_%d", i+1)}
+	}
+	buff, err := NewSheet("Sheet1").Export(&humans)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for page := 0; page < 4; page++ {
+		var last Progress
+		var data []Human
+		err := NewSheetFromReader(bytes.NewReader(buff.Bytes()), "Sheet1").Skip(page*3).Limit(3).
+			OnProgress(1, func(p Progress) { last = p }).Scan(&data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := humans[page*3 : min(page*3+3, len(humans))]
+		if fmt.Sprint(data) != fmt.Sprint(want) {
+			t.Errorf("page %d: unexpected data %v", page, data)
+		}
+		if rows := min(page*3+3, len(humans)); last.Rows != rows || last.Total != len(humans) {
+			t.Errorf("page %d: unexpected progress %+v", page, last)
+		}
+	}
+	var rows []Row
+	rows, err = NewSheetFromReader(bytes.NewReader(buff.Bytes()), "Sheet1").Skip(8).Limit(3).ScanMaps()
+	if err != nil || len(rows) != 2 || rows[0].Get("id") != "9" {
+		t.Errorf("unexpected rows %v, %v", rows, err)
+	}
+}
+
+func TestScanArea(t *testing.T) {
+	f := excelize.NewFile()
+	defer f.Close()
+	f.SetSheetRow("Sheet1", "A1", &[]string{"Orders of 2026"})
+	f.SetSheetRow("Sheet1", "B4", &[]string{"id", "name"})
+	f.SetSheetRow("Sheet1", "B5", &[]any{1, "Smith"})
+	f.SetSheetRow("Sheet1", "B6", &[]any{2, "Jack"})
+	f.SetSheetRow("Sheet1", "B8", &[]string{"notes"})
+	if err := f.AddTable("Sheet1", &excelize.Table{Range: "B4:C6", Name: "tblOrders"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.SetDefinedName(&excelize.DefinedName{Name: "Employees", RefersTo: "Sheet1!$B$4:$C$6"}); err != nil {
+		t.Fatal(err)
+	}
+	buff, err := f.WriteToBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sheets := []*Sheet{
+		NewSheetFromReader(bytes.NewReader(buff.Bytes()), "Sheet1").Range("B4:C6"),
+		NewSheetFromReader(bytes.NewReader(buff.Bytes()), "").DefinedName("Employees"),
+		NewSheetFromReader(bytes.NewReader(buff.Bytes()), "").Table("tblOrders"),
+	}
+	for _, sheet := range sheets {
+		var data []Human
+		if err := sheet.Scan(&data); err != nil {
+			t.Fatal(err)
+		}
+		if fmt.Sprint(data) != "[{1 Smith} {2 Jack}]" {
+			t.Errorf("unexpected data %v", data)
+		}
+	}
+}
+
+func TestScanBlocks(t *testing.T) {
+	f := excelize.NewFile()
+	defer f.Close()
+	f.SetSheetRow("Sheet1", "A1", &[]string{"North"})
+	f.SetSheetRow("Sheet1", "A2", &[]string{"id", "name"})
+	f.SetSheetRow("Sheet1", "A3", &[]any{1, "Smith"})
+	f.SetSheetRow("Sheet1", "A4", &[]any{2, "Jack"})
+	f.SetSheetRow("Sheet1", "A6", &[]string{"South"})
+	f.SetSheetRow("Sheet1", "A7", &[]string{"id", "name"})
+	f.SetSheetRow("Sheet1", "A8", &[]any{3, "James"})
+	buff, err := f.WriteToBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var blocks [][]Human
+	if err := NewSheetFromReader(bytes.NewReader(buff.Bytes()), "Sheet1").ScanBlocks(&blocks); err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(blocks) != "[[{1 Smith} {2 Jack}] [{3 James}]]" {
+		t.Errorf("unexpected blocks %v", blocks)
+	}
+	var regions map[string][]Human
+	if err := NewSheetFromReader(bytes.NewReader(buff.Bytes()), "Sheet1").ScanBlocks(&regions); err != nil {
+		t.Fatal(err)
+	}
+	if len(regions) != 2 || fmt.Sprint(regions["North"]) != "[{1 Smith} {2 Jack}]" || fmt.Sprint(regions["South"]) != "[{3 James}]" {
+		t.Errorf("unexpected regions %v", regions)
+	}
+	var named map[TestRegion][]Human
+	if err := NewSheetFromReader(bytes.NewReader(buff.Bytes()), "Sheet1").ScanBlocks(&named); err != nil {
+		t.Fatal(err)
+	}
+	if len(named) != 2 || fmt.Sprint(named["South"]) != "[{3 James}]" {
+		t.Errorf("unexpected regions %v", named)
+	}
+
+	f.SetCellStr("Sheet1", "A6", "North")
+	buff, _ = f.WriteToBuffer()
+	if err := NewSheetFromReader(buff, "Sheet1").ScanBlocks(&regions); err == nil || !strings.Contains(err.Error(), "duplicate title") {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+type TestRegion string
+
+type TestApostropheObject struct {
+	Name  string `xlsx:"Customer's name,unique"`
+	Label string `xlsx:"label,enum=a:Today's;b:O'Brien,default=a"`
+}
+
+func TestTagQuotes(t *testing.T) {
+	name := parseTag(reflect.TypeFor[TestApostropheObject]().Field(0))
+	if name.name != "Customer's name" || !name.unique {
+		t.Errorf("unexpected tag %+v", name)
+	}
+	label := parseTag(reflect.TypeFor[TestApostropheObject]().Field(1))
+	if fmt.Sprint(label.enum) != "[{a Today's} {b O'Brien}]" || label.def != "a" {
+		t.Errorf("unexpected tag %+v", label)
+	}
+	buff, err := NewSheet("Sheet1").Export(&[]TestApostropheObject{{"Smith", "b"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := excelize.OpenReader(buff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if rows, _ := f.GetRows("Sheet1"); fmt.Sprint(rows) != "[[Customer's name label] [Smith O'Brien]]" {
+		t.Errorf("unexpected rows %q", rows)
+	}
+}
+
+type TestBudget struct {
+	Department string  `xlsx:"Department,key"`
+	Month      string  `xlsx:"Month,pivot"`
+	Amount     float64 `xlsx:"Amount,value"`
+}
+
+func TestUnpivot(t *testing.T) {
+	budgets := []TestBudget{
+		{"Sales", "2026-02", 200},
+		{"Sales", "2026-01", 100},
+		{"R&D", "2026-01", 300},
+	}
+	buff, err := NewSheet("Budget").Unpivot().Export(&budgets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := excelize.OpenReader(bytes.NewReader(buff.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows, err := f.GetRows("Budget")
+	f.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(rows) != "[[Department 2026-01 2026-02] [Sales 100 200] [R&D 300]]" {
+		t.Errorf("unexpected matrix %v", rows)
+	}
+	var data []TestBudget
+	if err := NewSheetFromReader(buff, "Budget").Unpivot().Scan(&data); err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(data) != "[{Sales 2026-01 100} {Sales 2026-02 200} {R&D 2026-01 300}]" {
+		t.Errorf("unexpected records %v", data)
+	}
+}
+
+type TestRegionBudget struct {
+	Region     string  `xlsx:"Region,key"`
+	Department string  `xlsx:"Department,key"`
+	Month      string  `xlsx:"Month,pivot"`
+	Amount     float64 `xlsx:"Amount,value"`
+}
+
+func TestUnpivotExportFilter(t *testing.T) {
+	budgets := []TestRegionBudget{
+		{"East", "Sales", "2026-01", 100},
+		{"West", "R&D", "2026-01", 300},
+	}
+	buff, err := NewSheet("Budget").Unpivot().Filter(Schema{"Department": true, "Amount": true}).Export(&budgets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := excelize.OpenReader(buff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows, err := f.GetRows("Budget")
+	f.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(rows) != "[[Department 2026-01] [Sales 100] [R&D 300]]" {
+		t.Errorf("unexpected matrix %v", rows)
+	}
+
+	budgets = append(budgets, TestRegionBudget{"East", "Sales", "2026-01", 150})
+	_, err = NewSheet("Budget").Unpivot().Export(&budgets)
+	if err == nil || !strings.Contains(err.Error(), "record 3") {
+		t.Errorf("expected an error for the repeated record, got %v", err)
+	}
+}
+
+func TestMaps(t *testing.T) {
+	headers := []string{"sku", "price", "note"}
+	rows := []map[string]any{
+		{"sku": "A-1", "price": 9.5, "note": "new"},
+		{"sku": "B-2", "price": 12},
+	}
+	for _, stream := range []bool{false, true} {
+		var buff *bytes.Buffer
+		var err error
+		if stream {
+			buff, err = NewSheet("Items").StreamExportMaps(headers, rows)
+		} else {
+			buff, err = NewSheet("Items").ExportMaps(headers, rows)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		sheet := NewSheetFromReader(buff, "Items")
+		data, err := sheet.ScanMaps()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fmt.Sprint(sheet.Headers()) != fmt.Sprint(headers) {
+			t.Errorf("unexpected headers %v", sheet.Headers())
+		}
+		if len(data) != 2 || data[0].Get("price") != "9.5" || data[1].Get("sku") != "B-2" || data[1].Get("note") != "" {
+			t.Errorf("unexpected rows %v", data)
+		}
+	}
+}
+
+type TestSourceObject struct {
+	ID    int    `xlsx:"id"`
+	Name  string `xlsx:"name"`
+	Row   int    `xlsx:",rownum"`
+	Sheet string `xlsx:",sheetname"`
+}
+
+func TestRowNumAndSheetName(t *testing.T) {
+	buff, err := NewSheet("Orders").Export(&[]TestSourceObject{{ID: 1, Name: "Smith"}, {ID: 2, Name: "Jack"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := excelize.OpenReader(bytes.NewReader(buff.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.InsertRows("Orders", 1, 2)
+	f.SetCellStr("Orders", "A1", "Orders of 2026")
+	header, _ := f.GetRows("Orders")
+	if fmt.Sprint(header[2]) != "[id name]" {
+		t.Errorf("unexpected header %v", header[2])
+	}
+	buff, err = f.WriteToBuffer()
+	f.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var data []TestSourceObject
+	if err := NewSheetFromReader(buff, "Orders").Offset(2).Scan(&data); err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(data) != "[{1 Smith 4 Orders} {2 Jack 5 Orders}]" {
+		t.Errorf("unexpected data %v", data)
+	}
+}
+
+type TestSkipObject struct {
+	DBID      int       `xlsx:"-"`
+	ID        int       `xlsx:"id"`
+	Name      string    `xlsx:"name"`
+	secret    string    `xlsx:"secret"`
+	updatedAt time.Time `xlsx:"updated_at"`
+}
+
+func TestSkipFields(t *testing.T) {
+	objs := []TestSkipObject{{DBID: 7, ID: 1, Name: "Smith", secret: "x", updatedAt: time.Now()}}
+	for _, stream := range []bool{false, true} {
+		var buff *bytes.Buffer
+		var err error
+		if stream {
+			buff, err = NewSheet("Sheet1").StreamExport(&objs)
+		} else {
+			buff, err = NewSheet("Sheet1").Export(&objs)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		sheet := NewSheetFromReader(bytes.NewReader(buff.Bytes()), "Sheet1")
+		rows, err := sheet.ScanMaps()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fmt.Sprint(sheet.Headers()) != "[id name]" || len(rows) != 1 {
+			t.Errorf("unexpected headers %v", sheet.Headers())
+		}
+		var data []TestSkipObject
+		if err := NewSheetFromReader(buff, "Sheet1").Scan(&data); err != nil {
+			t.Fatal(err)
+		}
+		if len(data) != 1 || data[0].DBID != 0 || data[0].Name != "Smith" || data[0].secret != "" {
+			t.Errorf("unexpected data %+v", data)
+		}
+	}
+}
+
+var fuzzFieldTypes = []reflect.Type{
+	reflect.TypeOf(0),
+	reflect.TypeOf(uint8(0)),
+	reflect.TypeOf(0.0),
+	reflect.TypeOf(""),
+	reflect.TypeOf(false),
+	reflect.TypeOf(time.Time{}),
+	reflect.TypeOf(Sex(0)),
+	reflect.TypeOf(Time{}),
+	reflect.TypeOf(Picture{}),
+	reflect.TypeOf(Cell{}),
+	reflect.TypeOf(struct{ A int }{}),
+	reflect.TypeOf([]string{}),
+	reflect.TypeOf([2]int{}),
+	reflect.TypeOf(map[string]int{}),
+	reflect.TypeOf(make(chan int)),
+	reflect.TypeOf(func() {}),
+	reflect.TypeOf(new(int)),
+	reflect.TypeOf((*any)(nil)).Elem(),
+	reflect.TypeOf(complex128(0)),
+	reflect.TypeOf(Date{}),
+	reflect.TypeOf(TimeOfDay{}),
+	reflect.TypeOf(time.Duration(0)),
+	reflect.TypeOf(Percent(0)),
+}
+
+var fuzzFieldOptions = []string{"", ",unique", ",rownum", ",sheetname"}
+
+// fuzzRowType builds a struct type with a field for each pair of bytes in data.
+func fuzzRowType(data []byte) reflect.Type {
+	var fields []reflect.StructField
+	for i := 0; i+1 < len(data) && len(fields) < 8; i += 2 {
+		tag := fmt.Sprintf(`xlsx:"c%d%s"`, data[i+1]%4, fuzzFieldOptions[int(data[i+1]/4)%len(fuzzFieldOptions)])
+		if data[i+1] == 255 {
+			tag = `xlsx:"-"`
+		}
+		fields = append(fields, reflect.StructField{
+			Name: fmt.Sprintf("F%d", len(fields)),
+			Type: fuzzFieldTypes[int(data[i])%len(fuzzFieldTypes)],
+			Tag:  reflect.StructTag(tag),
+		})
+	}
+	return reflect.StructOf(fields)
+}
+
+// fuzzUnexportable are the types of fuzzFieldTypes that no export can write.
+var fuzzUnexportable = map[reflect.Type]bool{
+	reflect.TypeOf(struct{ A int }{}):  true,
+	reflect.TypeOf([]string{}):         true,
+	reflect.TypeOf([2]int{}):           true,
+	reflect.TypeOf(map[string]int{}):   true,
+	reflect.TypeOf(make(chan int)):     true,
+	reflect.TypeOf(func() {}):          true,
+	reflect.TypeOf((*any)(nil)).Elem(): true,
+	reflect.TypeOf(complex128(0)):      true,
+}
+
+// fuzzExportable reports whether every written field of rt can be exported.
+func fuzzExportable(rt reflect.Type, stream bool) bool {
+	for i := range rt.NumField() {
+		field := rt.Field(i)
+		tag := field.Tag.Get("xlsx")
+		if tag == "-" || strings.HasSuffix(tag, ",rownum") || strings.HasSuffix(tag, ",sheetname") {
+			continue
+		}
+		if fuzzUnexportable[field.Type] || stream && (field.Type == picReflectType || field.Type == cellReflectType) {
+			return false
+		}
+	}
+	return true
+}
+
+func FuzzStructShapes(f *testing.F) {
+	f.Add([]byte{0, 0, 3, 1, 5, 2})
+	f.Add([]byte{6, 0, 7, 9, 8, 2, 9, 3})
+	f.Add([]byte{10, 0, 11, 1, 13, 255})
+	f.Add([]byte{14, 0, 15, 1, 16, 2, 17, 3, 18, 4})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		rt := fuzzRowType(data)
+		rows := reflect.New(reflect.SliceOf(rt))
+		rows.Elem().Set(reflect.MakeSlice(reflect.SliceOf(rt), 2, 2))
+		book := reflect.New(reflect.StructOf([]reflect.StructField{
+			{Name: "Sheet1", Type: reflect.SliceOf(rt), Tag: `xlsx:"Sheet1"`},
+		}))
+
+		if _, err := NewSheet("Sheet1").Export(rows.Elem().Interface()); !errors.Is(err, ErrNotSlicePointer) {
+			t.Errorf("export of a slice value returned %v", err)
+		}
+		if err := (Excel{}).Scan(rows.Interface()); !errors.Is(err, ErrNotStructPointer) {
+			t.Errorf("scan of a slice pointer returned %v", err)
+		}
+
+		exports := []struct {
+			stream bool
+			export func() (*bytes.Buffer, error)
+		}{
+			{false, func() (*bytes.Buffer, error) { return NewSheet("Sheet1").Export(rows.Interface()) }},
+			{true, func() (*bytes.Buffer, error) { return NewSheet("Sheet1").StreamExport(rows.Interface()) }},
+			{false, func() (*bytes.Buffer, error) { return Excel{}.Export(book.Interface()) }},
+			{true, func() (*bytes.Buffer, error) { return Excel{}.StreamExport(book.Interface()) }},
+		}
+		for i, e := range exports {
+			buff, err := e.export()
+			if !fuzzExportable(rt, e.stream) {
+				if !errors.Is(err, ErrUnsupportedFieldType) {
+					t.Fatalf("export %d of %v returned %v, want ErrUnsupportedFieldType", i, rt, err)
+				}
+				continue
+			}
+			if errors.Is(err, ErrUnsupportedFieldType) {
+				t.Fatalf("export %d of %v: %v", i, rt, err)
+			}
+			if err != nil {
+				continue
+			}
+			data := buff.Bytes()
+			NewSheetFromReader(bytes.NewReader(data), "Sheet1").Scan(reflect.New(reflect.SliceOf(rt)).Interface())
+			NewSheetFromReader(bytes.NewReader(data), "Sheet1").ScanMaps()
+			NewExcelFromReader(bytes.NewReader(data)).Scan(reflect.New(book.Type().Elem()).Interface())
+		}
+	})
+}
+
+type Amount int64
+
+func (a Amount) MarshalXLSXCell(ctx CellContext) (Cell, error) {
+	return Cell{
+		Value:     strconv.FormatInt(int64(a), 10),
+		HyperLink: HyperLink{Link: "Sheet1!A1", Type: Location},
+		Style:     &excelize.Style{NumFmt: 3},
+	}, nil
+}
+
+func (a *Amount) UnmarshalXLSXCell(ctx CellContext, value string) error {
+	if ctx.Sheet != "Sheet1" || ctx.NumFmt != 3 || ctx.NumFmtCode != "" || !strings.HasPrefix(ctx.Cell, "B") {
+		return fmt.Errorf("unexpected context %+v", ctx)
+	}
+	v, err := strconv.ParseInt(ctx.Raw, 10, 64)
+	*a = Amount(v)
+	return err
+}
+
+type Price float64
+
+var priceNumFmt = `0.00 "USD"`
+
+func (p Price) MarshalXLSXCell(ctx CellContext) (Cell, error) {
+	return Cell{Value: strconv.FormatFloat(float64(p), 'f', -1, 64), Style: &excelize.Style{CustomNumFmt: &priceNumFmt}}, nil
+}
+
+func (p *Price) UnmarshalXLSXCell(ctx CellContext, value string) error {
+	if ctx.NumFmtCode != priceNumFmt {
+		return fmt.Errorf("unexpected context %+v", ctx)
+	}
+	v, err := strconv.ParseFloat(ctx.Raw, 64)
+	*p = Price(v)
+	return err
+}
+
+type BadMarshal struct{}
+
+func (BadMarshal) MarshalXLSX() string { return "" }
+
+type TestCellObject struct {
+	Name   string    `xlsx:"name"`
+	Amount Amount    `xlsx:"amount"`
+	Date   time.Time `xlsx:"date"`
+	Price  Price     `xlsx:"price"`
+}
+
+func TestCellMarshaler(t *testing.T) {
+	date := time.Date(2024, 5, 6, 0, 0, 0, 0, time.UTC)
+	buff, err := NewSheet("Sheet1").Export(&[]TestCellObject{{"Smith", 1234567, date, 9.5}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := excelize.OpenReader(bytes.NewReader(buff.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value, _ := f.GetCellValue("Sheet1", "B2"); value != "1234567" {
+		t.Errorf("unexpected value %q", value)
+	}
+	if ok, link, _ := f.GetCellHyperLink("Sheet1", "B2"); !ok || link != "Sheet1!A1" {
+		t.Errorf("unexpected hyperlink %q", link)
+	}
+	f.Close()
+	var data []TestCellObject
+	if err := NewSheetFromReader(buff, "Sheet1").Scan(&data); err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != 1 || data[0].Amount != 1234567 || !data[0].Date.Equal(date) || data[0].Price != 9.5 {
+		t.Errorf("unexpected data %+v", data)
+	}
+
+	if _, err := NewSheet("Sheet1").Export(&[]struct{ Bad BadMarshal }{{}}); !errors.Is(err, ErrUnsupportedFieldType) {
+		t.Errorf("unexpected error %v", err)
+	}
+	if _, err := NewSheet("Sheet1").StreamExport(&[]TestCellObject{{Amount: 1}}); err == nil {
+		t.Error("expected hyperlink error on stream export")
+	}
+}
+
+type Money struct {
+	cents int64
+}
+
+type TestMoneyObject struct {
+	Name  string `xlsx:"name"`
+	Price Money  `xlsx:"price"`
+	Sex   Sex    `xlsx:"sex"`
+}
+
+func TestConverters(t *testing.T) {
+	RegisterConverter(func(m Money) (any, error) {
+		return float64(m.cents) / 100, nil
+	}, func(s string) (Money, error) {
+		v, err := strconv.ParseFloat(s, 64)
+		return Money{cents: int64(math.Round(v * 100))}, err
+	})
+	sexes := AddConverter(NewConverters(), func(sex Sex) (any, error) {
+		return map[Sex]string{Male: "M", Female: "F"}[sex], nil
+	}, func(s string) (Sex, error) {
+		return map[string]Sex{"M": Male, "F": Female}[s], nil
+	})
+
+	buff, err := NewSheet("Sheet1").Converters(sexes).Export(&[]TestMoneyObject{{"Smith", Money{1234}, Female}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := excelize.OpenReader(bytes.NewReader(buff.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows, _ := f.GetRows("Sheet1")
+	if fmt.Sprint(rows) != "[[name price sex] [Smith 12.34 F]]" {
+		t.Errorf("unexpected rows %v", rows)
+	}
+	if typ, _ := f.GetCellType("Sheet1", "B2"); typ == excelize.CellTypeSharedString || typ == excelize.CellTypeInlineString {
+		t.Errorf("price is written as text")
+	}
+	f.Close()
+	var data []TestMoneyObject
+	if err := NewSheetFromReader(buff, "Sheet1").Converters(sexes).Scan(&data); err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != 1 || data[0].Price.cents != 1234 || data[0].Sex != Female {
+		t.Errorf("unexpected data %+v", data)
+	}
+}
+
+type TestEnumObject struct {
+	Name   string `xlsx:"name"`
+	Status int    `xlsx:"status,enum=1:Active;2:Suspended;3:Closed"`
+}
+
+func TestEnum(t *testing.T) {
+	objs := []TestEnumObject{{"Smith", 1}, {"Jones", 3}}
+	for _, stream := range []bool{false, true} {
+		var buff *bytes.Buffer
+		var err error
+		if stream {
+			buff, err = NewSheet("Sheet1").StreamExport(&objs)
+		} else {
+			buff, err = NewSheet("Sheet1").Export(&objs)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		f, err := excelize.OpenReader(bytes.NewReader(buff.Bytes()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		rows, _ := f.GetRows("Sheet1")
+		if fmt.Sprint(rows) != "[[name status] [Smith Active] [Jones Closed]]" {
+			t.Errorf("unexpected rows %v", rows)
+		}
+		dvs, err := f.GetDataValidations("Sheet1")
+		if err != nil || len(dvs) != 1 || dvs[0].Sqref != "B2:B1048576" || dvs[0].Formula1 != `"Active,Suspended,Closed"` {
+			t.Errorf("unexpected data validations %+v %v", dvs, err)
+		}
+		f.SetCellStr("Sheet1", "B3", "Deleted")
+		edited, err := f.WriteToBuffer()
+		f.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var data []TestEnumObject
+		err = NewSheetFromReader(edited, "Sheet1").Scan(&data)
+		var rowErr Error
+		if !errors.As(err, &rowErr) || !errors.Is(err, ErrUnknownEnumLabel) || rowErr.Field != "status" || rowErr.Value != "Deleted" {
+			t.Errorf("unexpected error %v", err)
+		}
+		data = nil
+		if err := NewSheetFromReader(buff, "Sheet1").Scan(&data); err != nil {
+			t.Fatal(err)
+		}
+		if fmt.Sprint(data) != "[{Smith 1} {Jones 3}]" {
+			t.Errorf("unexpected data %v", data)
+		}
+	}
+}
+
+type TestBoolObject struct {
+	Name    string `xlsx:"name"`
+	Active  bool   `xlsx:"active,bool=是|否"`
+	Visible bool   `xlsx:"visible"`
+}
+
+func TestBoolFormat(t *testing.T) {
+	buff, err := NewSheet("Sheet1").BoolFormat("Yes", "No").Export(&[]TestBoolObject{{"Smith", true, false}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := excelize.OpenReader(bytes.NewReader(buff.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	rows, _ := f.GetRows("Sheet1")
+	if fmt.Sprint(rows) != "[[name active visible] [Smith 是 No]]" {
+		t.Errorf("unexpected rows %v", rows)
+	}
+	for i, pair := range [][2]string{{"Y", "n"}, {"✓", "x"}, {"TRUE", "否"}, {"对", "0"}, {"是", "No"}} {
+		f.SetSheetRow("Sheet1", fmt.Sprintf("A%d", i+2), &[]any{"Smith", pair[0], pair[1]})
+	}
+	edited, err := f.WriteToBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var data []TestBoolObject
+	if err := NewSheetFromReader(edited, "Sheet1").Scan(&data); err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != 5 {
+		t.Fatalf("unexpected data %v", data)
+	}
+	for _, obj := range data {
+		if !obj.Active || obj.Visible {
+			t.Errorf("unexpected data %v", obj)
+		}
+	}
+	f.SetCellStr("Sheet1", "B2", "maybe")
+	edited, _ = f.WriteToBuffer()
+	if err := NewSheetFromReader(edited, "Sheet1").Scan(&data); err == nil {
+		t.Error("expected error for an invalid bool")
+	}
+}
+
+type TestEmptyObject struct {
+	Name  string   `xlsx:"name,required"`
+	Qty   int      `xlsx:"qty,default=1"`
+	Price *float64 `xlsx:"price"`
+	Code  string   `xlsx:"code,required"`
+}
+
+func TestEmptyCells(t *testing.T) {
+	price := 2.5
+	buff, err := NewSheet("Sheet1").Export(&[]TestEmptyObject{{Name: "a"}, {Name: "b", Qty: 3, Price: &price}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := excelize.OpenReader(bytes.NewReader(buff.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	f.SetCellStr("Sheet1", "B2", "")
+	f.RemoveCol("Sheet1", "D")
+	withoutCode, _ := f.WriteToBuffer()
+
+	var data []TestEmptyObject
+	if err := NewSheetFromReader(bytes.NewReader(withoutCode.Bytes()), "Sheet1").Scan(&data); err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != 2 || data[0].Qty != 1 || data[0].Price != nil || data[1].Qty != 3 || data[1].Price == nil || *data[1].Price != 2.5 {
+		t.Errorf("unexpected data %+v", data)
+	}
+	if err := NewSheetFromReader(bytes.NewReader(withoutCode.Bytes()), "Sheet1").EmptyCells(EmptyZero).Scan(&data); err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != 2 || data[0].Qty != 0 {
+		t.Errorf("unexpected data %+v", data)
+	}
+	err = NewSheetFromReader(bytes.NewReader(withoutCode.Bytes()), "Sheet1").EmptyCells(EmptyRequired).Scan(&data)
+	if !errors.Is(err, ErrMissingColumn) {
+		t.Errorf("unexpected error %v", err)
+	}
+	f.SetCellStr("Sheet1", "D1", "code")
+	f.SetCellStr("Sheet1", "D2", "x")
+	withCode, _ := f.WriteToBuffer()
+	err = NewSheetFromReader(withCode, "Sheet1").EmptyCells(EmptyRequired).Scan(&data)
+	var rowErr Error
+	if !errors.Is(err, ErrBlankCell) || !errors.As(err, &rowErr) || rowErr.Row.ID != 2 || rowErr.Field != "code" {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+type TestDefaultObject struct {
+	ID    int       `xlsx:"id"`
+	When  time.Time `xlsx:"when,default=2026-01-02T00:00:00Z"`
+	Start TimeOfDay `xlsx:"start,default=08:30"`
+}
+
+func TestDefaultMissingColumn(t *testing.T) {
+	f := excelize.NewFile()
+	defer f.Close()
+	f.SetSheetRow("Sheet1", "A1", &[]any{"id"})
+	f.SetSheetRow("Sheet1", "A2", &[]any{5})
+	buff, _ := f.WriteToBuffer()
+	var data []TestDefaultObject
+	if err := NewSheetFromReader(buff, "Sheet1").Scan(&data); err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != 1 || data[0].ID != 5 || !data[0].When.Equal(time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)) ||
+		data[0].Start != (TimeOfDay{8, 30, 0}) {
+		t.Errorf("unexpected data %v", data)
+	}
+}
+
+type TestNumberObject struct {
+	Amount float64 `xlsx:"amount"`
+	Rate   float64 `xlsx:"rate"`
+	Price  int     `xlsx:"price"`
+	Loss   int     `xlsx:"loss"`
+}
+
+func TestNumberFormat(t *testing.T) {
+	f := excelize.NewFile()
+	defer f.Close()
+	f.SetSheetRow("Sheet1", "A1", &[]any{"amount", "rate", "price", "loss"})
+	f.SetSheetRow("Sheet1", "A2", &[]any{"1,234.50", "12.5%", "¥1,000", "(1,234)"})
+	f.SetSheetRow("Sheet1", "A3", &[]any{1234.5, 0.125, 1000, -1234})
+	style, _ := f.NewStyle(&excelize.Style{NumFmt: 4})
+	percent, _ := f.NewStyle(&excelize.Style{NumFmt: 10})
+	f.SetCellStyle("Sheet1", "A3", "A3", style)
+	f.SetCellStyle("Sheet1", "B3", "B3", percent)
+	buff, _ := f.WriteToBuffer()
+
+	want := "[{1234.5 0.125 1000 -1234} {1234.5 0.125 1000 -1234}]"
+	var data []TestNumberObject
+	if err := NewSheetFromReader(bytes.NewReader(buff.Bytes()), "Sheet1").Scan(&data); err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(data) != want {
+		t.Errorf("unexpected data %v", data)
+	}
+	if err := NewSheetFromReader(bytes.NewReader(buff.Bytes()), "Sheet1").RawCellValue().Scan(&data); err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(data) != want {
+		t.Errorf("unexpected raw data %v", data)
+	}
+
+	f.SetSheetRow("Sheet1", "A2", &[]any{"1.234,50", "12,5 %", "1.000 €", "-1.234"})
+	f.RemoveRow("Sheet1", 3)
+	buff, _ = f.WriteToBuffer()
+	if err := NewSheetFromReader(buff, "Sheet1").NumberFormat(',', '.').Scan(&data); err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(data) != "[{1234.5 0.125 1000 -1234}]" {
+		t.Errorf("unexpected data %v", data)
+	}
+
+	for _, row := range [][]any{{"1,5", 0, 0, 0}, {0, 0, "1,5", 0}, {"1.234,50", 0, 0, 0}, {"12,34.5", 0, 0, 0}} {
+		f.SetSheetRow("Sheet1", "A2", &row)
+		buff, _ = f.WriteToBuffer()
+		err := NewSheetFromReader(buff, "Sheet1").Scan(&data)
+		if err == nil || !strings.Contains(err.Error(), "invalid number") {
+			t.Errorf("%v: unexpected error %v", row, err)
+		}
+	}
+}
+
+type TestIDObject struct {
+	ID   string `xlsx:"id"`
+	Card int64  `xlsx:"card"`
+	Rate float64
+}
+
+func TestLongNumbers(t *testing.T) {
+	f := excelize.NewFile()
+	defer f.Close()
+	f.SetSheetRow("Sheet1", "A1", &[]any{"id", "card", "Rate"})
+	f.SetSheetRow("Sheet1", "A2", &[]any{123456789012, 1234567890123, 1.5e20})
+	f.SetSheetRow("Sheet1", "A3", &[]any{"110101199001011234", 1, 1})
+	buff, _ := f.WriteToBuffer()
+	var data []TestIDObject
+	if err := NewSheetFromReader(bytes.NewReader(buff.Bytes()), "Sheet1").Scan(&data); err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(data) != "[{123456789012 1234567890123 1.5e+20} {110101199001011234 1 1}]" {
+		t.Errorf("unexpected data %v", data)
+	}
+
+	f.SetCellValue("Sheet1", "A3", 110101199001011234)
+	buff, _ = f.WriteToBuffer()
+	err := NewSheetFromReader(buff, "Sheet1").Scan(&data)
+	var rowErr Error
+	if !errors.Is(err, ErrPrecisionLoss) || !errors.As(err, &rowErr) || rowErr.Row.ID != 2 {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	for _, stream := range []bool{false, true} {
+		objs := []TestIDObject{{"00123", 1 << 60, 0.5}, {"A-1", 1, 1}}
+		if stream {
+			buff, err = NewSheet("Sheet1").StreamExport(&objs)
+		} else {
+			buff, err = NewSheet("Sheet1").Export(&objs)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		out, err := excelize.OpenReader(buff)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for axis, text := range map[string]bool{"A2": true, "B2": true, "C2": false, "A3": false, "B3": false} {
+			id, _ := out.GetCellStyle("Sheet1", axis)
+			style, _ := out.GetStyle(id)
+			if (style.NumFmt == 49) != text {
+				t.Errorf("unexpected number format %d of %s", style.NumFmt, axis)
+			}
+		}
+		out.Close()
+	}
+}
+
+type TestLayoutObject struct {
+	Name string    `xlsx:"name"`
+	Date time.Time `xlsx:"date,layout=2006/01/02|02.01.2006 15:04,tz=Asia/Shanghai"`
+	At   time.Time `xlsx:"at,tz=Asia/Shanghai"`
+}
+
+func TestTimeLayout(t *testing.T) {
+	shanghai, err := time.LoadLocation("Asia/Shanghai")
+	if err != nil {
+		t.Skip(err)
+	}
+	f := excelize.NewFile()
+	defer f.Close()
+	f.SetSheetRow("Sheet1", "A1", &[]any{"name", "date", "at"})
+	f.SetSheetRow("Sheet1", "A2", &[]any{"2026/10/18", "2026/10/18", time.Date(2026, 10, 18, 8, 0, 0, 0, time.UTC)})
+	f.SetSheetRow("Sheet1", "A3", &[]any{"x", "18.10.2026 14:30", nil})
+	buff, _ := f.WriteToBuffer()
+	var data []TestLayoutObject
+	if err := NewSheetFromReader(buff, "Sheet1").Scan(&data); err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != 2 ||
+		!data[0].Date.Equal(time.Date(2026, 10, 18, 0, 0, 0, 0, shanghai)) ||
+		!data[0].At.Equal(time.Date(2026, 10, 18, 8, 0, 0, 0, shanghai)) ||
+		!data[1].Date.Equal(time.Date(2026, 10, 18, 14, 30, 0, 0, shanghai)) || !data[1].At.IsZero() {
+		t.Fatalf("unexpected data %v", data)
+	}
+
+	objs := []TestLayoutObject{{"a", time.Date(2026, 10, 17, 20, 0, 0, 0, time.UTC), time.Date(2026, 10, 17, 20, 0, 0, 0, time.UTC)}}
+	for _, stream := range []bool{false, true} {
+		if stream {
+			buff, err = NewSheet("Sheet1").StreamExport(&objs)
+		} else {
+			buff, err = NewSheet("Sheet1").Export(&objs)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		out, err := excelize.OpenReader(bytes.NewReader(buff.Bytes()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if value, _ := out.GetCellValue("Sheet1", "B2"); value != "2026/10/18" {
+			t.Errorf("unexpected date %q", value)
+		}
+		out.Close()
+		data = nil
+		if err := NewSheetFromReader(buff, "Sheet1").Scan(&data); err != nil {
+			t.Fatal(err)
+		}
+		if len(data) != 1 || !data[0].At.Equal(objs[0].At) {
+			t.Errorf("unexpected data %v", data)
+		}
+	}
+}
+
+type TestSerialObject struct {
+	Birthday Date          `xlsx:"birthday"`
+	Start    TimeOfDay     `xlsx:"start"`
+	SLA      time.Duration `xlsx:"sla"`
+	Rate     Percent       `xlsx:"rate"`
+}
+
+func TestSerialTypes(t *testing.T) {
+	objs := []TestSerialObject{
+		{Date{1990, time.May, 17}, TimeOfDay{8, 30, 0}, 36 * time.Hour, 0.125},
+		{Date{}, TimeOfDay{23, 59, 59}, 90 * time.Minute, -0.5},
+	}
+	want := [][]string{
+		{"birthday", "start", "sla", "rate"},
+		{"1990-05-17", "08:30:00", "36:00:00", "12.50%"},
+		{"", "23:59:59", "1:30:00", "-50.00%"},
+	}
+	for _, stream := range []bool{false, true} {
+		var buff *bytes.Buffer
+		var err error
+		if stream {
+			buff, err = NewSheet("Sheet1").StreamExport(&objs)
+		} else {
+			buff, err = NewSheet("Sheet1").Export(&objs)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		f, err := excelize.OpenReader(bytes.NewReader(buff.Bytes()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		rows, _ := f.GetRows("Sheet1")
+		f.Close()
+		if !reflect.DeepEqual(rows, want) {
+			t.Errorf("stream %v: unexpected rows %q", stream, rows)
+		}
+		var data []TestSerialObject
+		if err := NewSheetFromReader(buff, "Sheet1").Scan(&data); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(data, objs) {
+			t.Errorf("stream %v: unexpected data %v", stream, data)
+		}
+	}
+
+	f := excelize.NewFile()
+	defer f.Close()
+	f.SetSheetRow("Sheet1", "A1", &[]any{"birthday", "start", "sla", "rate"})
+	f.SetSheetRow("Sheet1", "A2", &[]any{"1990-05-17", "08:30", "1h30m", "12.5%"})
+	f.SetSheetRow("Sheet1", "A3", &[]any{"17/05/1990", "8:30:00", "-36:00", "0.125"})
+	f.SetSheetRow("Sheet1", "A4", &[]any{"May 17, 1990", "8:30 AM", "0s", "0"})
+	buff, _ := f.WriteToBuffer()
+	var data []struct {
+		Birthday Date          `xlsx:"birthday,layout='02/01/2006|Jan 2, 2006'"`
+		Start    TimeOfDay     `xlsx:"start,format=3:04 PM"`
+		SLA      time.Duration `xlsx:"sla"`
+		Rate     Percent       `xlsx:"rate"`
+	}
+	if err := NewSheetFromReader(buff, "Sheet1").Scan(&data); err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != 3 || data[0].Birthday != data[1].Birthday || data[2].Birthday != data[1].Birthday || data[0].Birthday != (Date{1990, time.May, 17}) ||
+		data[0].Start != data[1].Start || data[0].Start != (TimeOfDay{8, 30, 0}) ||
+		data[0].SLA != 90*time.Minute || data[1].SLA != -36*time.Hour ||
+		data[0].Rate != 0.125 || data[1].Rate != 0.125 {
+		t.Fatalf("unexpected data %v", data)
+	}
+}
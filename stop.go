@@ -0,0 +1,60 @@
+package excel
+
+import (
+	"regexp"
+	"strings"
+)
+
+// StopAtBlankRow stops scanning at the first row whose cells are all blank.
+func (s *Sheet) StopAtBlankRow() *Sheet {
+	s.stopAtBlank = true
+	return s
+}
+
+// StopAt stops scanning at the first row whose first cell satisfies fn, e.g. a "Total" footer.
+func (s *Sheet) StopAt(fn func(first string) bool) *Sheet {
+	s.stopAt = fn
+	return s
+}
+
+// StopAtRegexp stops scanning at the first row whose first cell matches re.
+func (s *Sheet) StopAtRegexp(re *regexp.Regexp) *Sheet {
+	return s.StopAt(re.MatchString)
+}
+
+// Limit sets the maximum number of rows to scan after the header, 0 means no limit.
+// With Skip it pages through the rows, e.g. Skip(20).Limit(10) scans the third page of 10.
+func (s *Sheet) Limit(n int) *Sheet {
+	s.limit = n
+	return s
+}
+
+// Skip skips the first n non-blank rows after the header, unlike Offset which moves the header.
+func (s *Sheet) Skip(n int) *Sheet {
+	s.skip = n
+	return s
+}
+
+// stopRow reports whether scanning should stop before row.
+func (s *Sheet) stopRow(row []string) bool {
+	if s.stopAtBlank && isBlankRow(row) {
+		return true
+	}
+	if s.stopAt != nil {
+		var first string
+		if len(row) > 0 {
+			first = strings.TrimSpace(row[0])
+		}
+		return s.stopAt(first)
+	}
+	return false
+}
+
+func isBlankRow(row []string) bool {
+	for _, cell := range row {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
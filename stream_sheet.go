@@ -2,126 +2,180 @@ package excel
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"reflect"
+	"time"
 
 	"github.com/cuishu/functools"
 	excelize "github.com/xuri/excelize/v2"
 )
 
 func (s *Sheet) streamExportTitle(writer *excelize.StreamWriter, schema Schema, t reflect.Type) error {
-	title := titleRow(schema, t)
+	return s.streamWriteTitle(writer, titleRow(schema, t))
+}
+
+func (s *Sheet) streamWriteTitle(writer *excelize.StreamWriter, title []string) error {
+	s.title = title
 	s.colCnt = len(title)
 	if s.useTextStyle {
 		writer.SetColStyle(1, s.colCnt, s.style)
 	}
-	return writer.SetRow("A1", functools.Map(func(v string) any {
+	if s.headerRowCount() == 1 {
+		return writer.SetRow("A1", s.streamTitleCells(title))
+	}
+	grid, merges := headerLayout(title, s.headerRowCount())
+	for r, row := range grid {
+		if err := writer.SetRow(cell(r+1, 1), s.streamTitleCells(row)); err != nil {
+			return err
+		}
+	}
+	for _, m := range merges {
+		if err := writer.MergeCell(m.top, m.bottom); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Sheet) streamTitleCells(title []string) []any {
+	return functools.Map(func(v string) any {
 		return &excelize.Cell{
 			StyleID: s.style,
 			Formula: "",
 			Value:   v,
 		}
-	}, title))
+	}, title)
 }
 
-func (s *Sheet) streamExportStruct(field reflect.Value) (any, error) {
-	if field.Type() == picReflectType {
-		panic("pic type not support")
-	} else if field.Type() == cellReflectType {
-		panic("cell type not support")
-	}
-	fun, ok := field.Type().MethodByName("MarshalXLSX")
-	if !ok {
-		fun, ok = field.Type().MethodByName("MarshalText")
-	}
-	if ok {
-		res := fun.Func.Call([]reflect.Value{field})
-		if res[1].Interface() != nil {
-			err, ok := res[1].Interface().(error)
-			if !ok {
-				return nil, fmt.Errorf("%s has invalid return type", fun.Name)
+// streamCell returns the cell written for a field at axis.
+func (s *Sheet) streamCell(f *excelize.File, field reflect.Value, tag fieldTag, axis string) (*excelize.Cell, error) {
+	for field.Kind() == reflect.Pointer && s.encoder(field.Type()) == nil {
+		if field.IsNil() {
+			return &excelize.Cell{StyleID: s.style}, nil
+		}
+		field = field.Elem()
+	}
+	if len(tag.enum) > 0 {
+		value, err := s.formatValue(field)
+		if err != nil {
+			return nil, err
+		}
+		return &excelize.Cell{StyleID: s.style, Value: tag.enumLabel(value)}, nil
+	}
+	if encode := s.encoder(field.Type()); encode != nil {
+		value, err := encode(field)
+		if err != nil {
+			return nil, err
+		}
+		return &excelize.Cell{StyleID: s.style, Value: value}, nil
+	}
+	if m, ok := as[CellMarshaler](field); ok {
+		c, err := m.MarshalXLSXCell(s.exportContext(axis))
+		if err != nil {
+			return nil, err
+		}
+		if c.HyperLink.Link != "" {
+			return nil, fmt.Errorf("%s: hyperlink is not supported by stream export", axis)
+		}
+		style := s.style
+		if c.Style != nil {
+			if style, err = f.NewStyle(c.Style); err != nil {
+				return nil, err
 			}
+		}
+		return &excelize.Cell{StyleID: style, Value: c.Value}, nil
+	}
+	switch {
+	case field.Type() == picReflectType, field.Type() == cellReflectType:
+		return nil, fmt.Errorf("%w: %s is not supported by stream export", ErrUnsupportedFieldType, field.Type())
+	case isSerial(field.Type()):
+		if blankSerial(field) {
+			return &excelize.Cell{StyleID: s.style}, nil
+		}
+		return &excelize.Cell{StyleID: s.serialStyles[field.Type()], Value: s.serialValue(field)}, nil
+	case isTime(field.Type()):
+		value, err := timeValue(field.Interface().(time.Time), tag)
+		if err != nil {
 			return nil, err
 		}
-		return toString(res[0].Interface()), nil
-	} else if isTime(field.Type()) {
-		return field.Interface(), nil
-	} else {
-		panic("struct type must implement MarshalXLSX or MarshalText")
+		return &excelize.Cell{StyleID: s.style, Value: value}, nil
+	case field.Kind() == reflect.Bool && !implements(field.Type(), exportInterfaceTypes):
+		return &excelize.Cell{StyleID: s.style, Value: s.boolText(field.Bool(), tag)}, nil
+	}
+	value, err := s.formatValue(field)
+	if err != nil {
+		return nil, err
 	}
+	if keepText(field, value) {
+		return &excelize.Cell{StyleID: s.textStyle, Value: value}, nil
+	}
+	return &excelize.Cell{StyleID: s.style, Value: value}, nil
 }
 
-func (s *Sheet) streamExportRow(writer *excelize.StreamWriter, obj reflect.Value, col column) error {
+func (s *Sheet) streamExportRow(f *excelize.File, writer *excelize.StreamWriter, obj reflect.Value, col column) error {
 	var rowData []any = make([]any, 0, obj.NumField())
+	var start string
 	t := obj.Type()
 	for i := 0; i < obj.NumField(); i++ {
-		field := obj.Field(i)
-		if field.Kind() == reflect.Struct {
-			if data, err := s.streamExportStruct(field); err != nil {
-				return err
-			} else {
-				rowData = append(rowData, data)
-			}
-		} else {
-			tag := getFieldName(t.Field(i))
-			show, ok := s.filter[tag]
-			if (len(s.filter) == 0) || (show && ok) {
-				if field.NumMethod() > 0 {
-					fun, ok := field.Type().MethodByName("MarshalXLSX")
-					if !ok {
-						fun, ok = field.Type().MethodByName("MarshalText")
-					}
-					if ok {
-						res := fun.Func.Call([]reflect.Value{field})
-						if res[1].Interface() != nil {
-							err, ok := res[1].Interface().(error)
-							if !ok {
-								return fmt.Errorf("%s has invalid return type", fun.Name)
-							}
-							return err
-						}
-						rowData = append(rowData, toString(res[0].Interface()))
-						continue
-					}
-				}
-				rowData = append(rowData, toString(field.Interface()))
-			}
+		if !exported(t.Field(i)) || !s.shown(t.Field(i)) {
+			continue
 		}
-	}
-	if err := writer.SetRow(col(), functools.Map(func(v any) any {
-		return &excelize.Cell{
-			StyleID: s.style,
-			Formula: "",
-			Value:   v,
+		axis := col()
+		if start == "" {
+			start = axis
 		}
-	}, rowData)); err != nil {
-		return err
+		c, err := s.streamCell(f, obj.Field(i), parseTag(t.Field(i)), axis)
+		if err != nil {
+			return err
+		}
+		rowData = append(rowData, c)
 	}
-	return nil
+	if start == "" {
+		start = col()
+	}
+	return writer.SetRow(start, rowData)
 }
 
-func (s *Sheet) streamExportRows(writer *excelize.StreamWriter, slice reflect.Value) error {
-	rowNum := 1
+func (s *Sheet) streamExportRows(ctx context.Context, f *excelize.File, writer *excelize.StreamWriter, slice reflect.Value) error {
+	rowNum := s.headerRowCount()
 	n := slice.Len()
+	progress := s.newProgress(n)
 	for i := range n {
+		if err := s.checkContext(ctx, i); err != nil {
+			return err
+		}
+		progress.report(i)
 		rowNum++
 		obj := slice.Index(i)
-		if err := s.streamExportRow(writer, obj, cellGenerator(rowNum)); err != nil {
+		if err := s.streamExportRow(f, writer, obj, cellGenerator(rowNum)); err != nil {
 			return err
 		}
 	}
+	progress.done(n)
 	s.rowCnt = n
 	return nil
 }
 
-func (s *Sheet) sheetStreamExport(f *excelize.File, rv reflect.Value) error {
+func (s *Sheet) sheetStreamExport(ctx context.Context, f *excelize.File, rv reflect.Value) error {
+	if s.unpivot {
+		return errors.New("unpivot is not supported by stream export")
+	}
 	t := rv.Type().Elem().Elem()
+	if err := s.initStyles(f); err != nil {
+		return err
+	}
 	index, err := f.NewSheet(s.sheet)
 	if err != nil {
 		return err
 	}
 	f.SetActiveSheet(index)
+	// the stream writer keeps the data validations added before it is created
+	if err := s.addEnumValidations(f, t); err != nil {
+		return err
+	}
 	writer, err := f.NewStreamWriter(s.sheet)
 	if err != nil {
 		return err
@@ -133,17 +187,26 @@ func (s *Sheet) sheetStreamExport(f *excelize.File, rv reflect.Value) error {
 
 	slice := rv.Elem()
 
-	if err := s.streamExportRows(writer, slice); err != nil {
+	if err := s.streamExportRows(ctx, f, writer, slice); err != nil {
 		return err
 	}
 	return writer.Flush()
 }
 
+// StreamExport exports the sheet to a bytes.Buffer with a stream writer.
 func (s *Sheet) StreamExport(v any) (*bytes.Buffer, error) {
+	return s.StreamExportContext(context.Background(), v)
+}
+
+// StreamExportContext is like StreamExport but stops writing when ctx is done.
+func (s *Sheet) StreamExportContext(ctx context.Context, v any) (*bytes.Buffer, error) {
+	rv, err := s.checkSlice(v, opStreamExport)
+	if err != nil {
+		return nil, err
+	}
 	f := excelize.NewFile()
 	defer f.Close()
-	rv := reflect.ValueOf(v)
-	if err := s.sheetStreamExport(f, rv); err != nil {
+	if err := s.sheetStreamExport(ctx, f, rv); err != nil {
 		return nil, err
 	}
 
@@ -151,12 +214,15 @@ func (s *Sheet) StreamExport(v any) (*bytes.Buffer, error) {
 }
 
 func (s *Sheet) StreamExportTo(writer io.Writer, v any) error {
+	rv, err := s.checkSlice(v, opStreamExport)
+	if err != nil {
+		return err
+	}
 	f := excelize.NewFile()
 	defer f.Close()
-	rv := reflect.ValueOf(v)
-	if err := s.sheetStreamExport(f, rv); err != nil {
+	if err := s.sheetStreamExport(context.Background(), f, rv); err != nil {
 		return err
 	}
-	_, err := f.WriteTo(writer)
+	_, err = f.WriteTo(writer)
 	return err
 }
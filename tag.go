@@ -0,0 +1,126 @@
+package excel
+
+import (
+	"reflect"
+	"strings"
+)
+
+// layoutKV is the layout of a sheet holding label/value pairs in its first two columns.
+const layoutKV = "kv"
+
+// fieldTag is the parsed form of the xlsx struct tag, the first item is the
+// column name and the rest are options, e.g. `xlsx:"sku,unique"`. A value in
+// single quotes after "=" may hold commas, e.g. `xlsx:"date,layout='Jan 2, 2006'"`.
+type fieldTag struct {
+	name     string
+	skip     bool
+	unique   bool
+	ref      string
+	layout   string
+	key      bool
+	pivot    bool
+	value    bool
+	enum     []enumItem
+	bools    boolWords
+	def      string
+	format   string
+	tz       string
+	required bool
+
+	rownum    bool
+	sheetname bool
+}
+
+// meta reports whether the field holds where a row comes from instead of a column,
+// such fields are filled by Scan and skipped by Export.
+func (tag fieldTag) meta() bool {
+	return tag.rownum || tag.sheetname
+}
+
+func parseTag(field reflect.StructField) fieldTag {
+	raw := field.Tag.Get("xlsx")
+	if raw == "-" {
+		return fieldTag{name: field.Name, skip: true}
+	}
+	items := splitTag(raw)
+	tag := fieldTag{name: strings.TrimSpace(items[0])}
+	if tag.name == "" {
+		tag.name = field.Name
+	}
+	for _, item := range items[1:] {
+		key, value, _ := strings.Cut(strings.TrimSpace(item), "=")
+		if len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'' {
+			value = value[1 : len(value)-1]
+		}
+		switch key {
+		case "unique":
+			tag.unique = true
+		case "ref":
+			tag.ref = value
+		case "layout":
+			tag.layout = value
+		case "key":
+			tag.key = true
+		case "pivot":
+			tag.pivot = true
+		case "value":
+			tag.value = true
+		case "enum":
+			tag.enum = parseEnum(value)
+		case "bool":
+			tag.bools = parseBoolWords(value)
+		case "default":
+			tag.def = value
+		case "format":
+			tag.format = value
+		case "tz":
+			tag.tz = value
+		case "required":
+			tag.required = true
+		case "rownum":
+			tag.rownum = true
+		case "sheetname":
+			tag.sheetname = true
+		}
+	}
+	return tag
+}
+
+// splitTag splits the xlsx tag at the commas outside quoted option values. A quote only
+// opens a value right after "=" and closes it before a comma or the end of the tag, so
+// apostrophes elsewhere, like in `xlsx:"Customer's name"`, are kept as they are.
+func splitTag(raw string) []string {
+	var items []string
+	quoted, start := false, 0
+	for i := 0; i < len(raw); i++ {
+		switch raw[i] {
+		case '\'':
+			if !quoted && i > 0 && raw[i-1] == '=' {
+				quoted = true
+			} else if quoted && (i+1 == len(raw) || raw[i+1] == ',') {
+				quoted = false
+			}
+		case ',':
+			if !quoted {
+				items = append(items, raw[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(items, raw[start:])
+}
+
+func getFieldName(field reflect.StructField) string {
+	return parseTag(field).name
+}
+
+// ignored reports whether the field is left out of both Scan and Export,
+// which are the unexported fields and the fields tagged `xlsx:"-"`.
+func ignored(field reflect.StructField) bool {
+	return !field.IsExported() || parseTag(field).skip
+}
+
+// exported reports whether the field is written as a column by Export.
+func exported(field reflect.StructField) bool {
+	return !ignored(field) && !parseTag(field).meta()
+}
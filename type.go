@@ -4,8 +4,6 @@ import (
 	"fmt"
 	"reflect"
 	"strconv"
-
-	"github.com/go-playground/validator/v10"
 )
 
 func parseInt8(s string) (reflect.Value, error) {
@@ -118,14 +116,23 @@ func parseFloat64(s string) (reflect.Value, error) {
 
 func parseBool(s string) (reflect.Value, error) {
 	var rv reflect.Value
-	v, err := strconv.ParseBool(s)
+	v, err := parseBoolWord(s)
 	if err != nil {
 		return rv, err
 	}
 	return reflect.ValueOf(v), nil
 }
 
+// getReflectValue parses s into a value of type t, which may be a named type of a basic kind.
 func getReflectValue(s string, t reflect.Type) (reflect.Value, error) {
+	rv, err := parseValue(s, t)
+	if err != nil || !rv.IsValid() {
+		return rv, err
+	}
+	return rv.Convert(t), nil
+}
+
+func parseValue(s string, t reflect.Type) (reflect.Value, error) {
 	var rv reflect.Value
 	switch t.Kind() {
 	case reflect.String:
@@ -174,8 +181,6 @@ func isTime(rt reflect.Type) bool {
 	return false
 }
 
-var validate = validator.New()
-
 var twentySixTable = []string{"", "A", "B", "C", "D", "E", "F", "G", "H", "I", "J", "K", "L", "M", "N", "O", "P", "Q", "R", "S", "T", "U", "V", "W", "X", "Y", "Z"}
 
 func toTwentySix(n int) string {
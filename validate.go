@@ -0,0 +1,121 @@
+package excel
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+	"weak"
+
+	"github.com/go-playground/locales"
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/zh"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	en_translations "github.com/go-playground/validator/v10/translations/en"
+	zh_translations "github.com/go-playground/validator/v10/translations/zh"
+)
+
+var validate = validator.New()
+
+var bundledLocales = map[string]func() locales.Translator{
+	"en": en.New,
+	"zh": zh.New,
+}
+
+var defaultTranslations = map[string]func(*validator.Validate, ut.Translator) error{
+	"en": en_translations.RegisterDefaultTranslations,
+	"zh": zh_translations.RegisterDefaultTranslations,
+}
+
+// translationKey holds the validator weakly, so its translations are dropped with it.
+type translationKey struct {
+	validate weak.Pointer[validator.Validate]
+	locale   string
+}
+
+// translation is the translator of a validator for a locale, the default translations
+// are registered on the validator once.
+type translation struct {
+	once  sync.Once
+	trans ut.Translator
+	err   error
+}
+
+var translations sync.Map
+
+// Translator returns the translator of locale used with v, or with the default validator
+// if v is nil, "en" and "zh" are supported. Every validator has its own translator holding
+// the default translations, translations for custom tags can be registered on it.
+// The default translations are registered on v by the first call, so every locale of a
+// validator should be set up before it is used by concurrent scans.
+func Translator(v *validator.Validate, locale string) (ut.Translator, error) {
+	t := translationOf(v, locale)
+	return t.trans, t.err
+}
+
+// translationOf returns the translation of locale for v, registering it on the first call.
+func translationOf(v *validator.Validate, locale string) *translation {
+	newLocale, ok := bundledLocales[locale]
+	if !ok {
+		return &translation{err: fmt.Errorf("unsupported locale %q", locale)}
+	}
+	if v == nil {
+		v = validate
+	}
+	key := translationKey{validate: weak.Make(v), locale: locale}
+	value, loaded := translations.LoadOrStore(key, &translation{})
+	if !loaded && v != validate {
+		runtime.AddCleanup(v, func(key translationKey) { translations.Delete(key) }, key)
+	}
+	t := value.(*translation)
+	t.once.Do(func() {
+		t.trans, _ = ut.New(en.New(), newLocale()).GetTranslator(locale)
+		t.err = defaultTranslations[locale](v, t.trans)
+	})
+	return t
+}
+
+// translate sets up the translation of the messages of the validator for the locale.
+func (s *Sheet) translate() {
+	s.translation = nil
+	if s.locale != "" {
+		s.translation = translationOf(s.validate, s.locale)
+	}
+}
+
+// translate sets up the translation of the messages of the validator for the locale.
+func (e *Excel) translate() {
+	e.translation = nil
+	if e.locale != "" {
+		e.translation = translationOf(e.validate, e.locale)
+	}
+}
+
+func (s *Sheet) validator() *validator.Validate {
+	if s.validate != nil {
+		return s.validate
+	}
+	return validate
+}
+
+func (s *Sheet) validateVar(value any, tag string) error {
+	v := s.validator()
+	err := v.Var(value, tag)
+	if err == nil || s.translation == nil {
+		return err
+	}
+	var errs validator.ValidationErrors
+	if !errors.As(err, &errs) {
+		return err
+	}
+	if s.translation.err != nil {
+		return s.translation.err
+	}
+	mesgs := make([]string, 0, len(errs))
+	for _, e := range errs {
+		mesgs = append(mesgs, strings.TrimSpace(e.Translate(s.translation.trans)))
+	}
+	return errors.New(strings.Join(mesgs, "; "))
+}